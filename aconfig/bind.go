@@ -0,0 +1,342 @@
+package aconfig
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// ByteSize is a size in bytes, parsed from values such as 512, "64KB" or "1.5GiB".
+// K, M, G and T units are multiples of 1024 with or without the B/iB suffix.
+type ByteSize int64
+
+const (
+	Byte     ByteSize = 1
+	KiloByte          = 1024 * Byte
+	MegaByte          = 1024 * KiloByte
+	GigaByte          = 1024 * MegaByte
+	TeraByte          = 1024 * GigaByte
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"kib", KiloByte}, {"mib", MegaByte}, {"gib", GigaByte}, {"tib", TeraByte},
+	{"kb", KiloByte}, {"mb", MegaByte}, {"gb", GigaByte}, {"tb", TeraByte},
+	{"k", KiloByte}, {"m", MegaByte}, {"g", GigaByte}, {"t", TeraByte},
+	{"b", Byte},
+}
+
+func ParseByteSize(s string) (ByteSize, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	unit := Byte
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	return ByteSize(f * float64(unit)), nil
+}
+
+func toByteSizeE(i interface{}) (ByteSize, error) {
+	if s, ok := i.(string); ok {
+		return ParseByteSize(s)
+	}
+	n, err := cast.ToInt64E(i)
+	if err != nil {
+		return 0, err
+	}
+
+	return ByteSize(n), nil
+}
+
+func (kv KV) GetByteSize(key string) ByteSize {
+	size, _ := toByteSizeE(kv.get(key))
+	return size
+}
+
+func (kv KV) GetByteSizeE(path string) (ByteSize, error) {
+	var out ByteSize
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = toByteSizeE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetByteSizeOrDefault(path string, defaultValue ByteSize) ByteSize {
+	if out, err := kv.GetByteSizeE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+	urlType      = reflect.TypeOf(url.URL{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Bind decodes kv into the struct pointed by out. Keys are matched against the json tag of the fields
+// like LoadTo, and the fields honor the tags:
+//
+//	default:"5s"      value used when the key is missing
+//	required:"true"   the key must be present
+//	min:"1" max:"10"  range of numbers and durations, or length of strings, slices and maps
+//
+// time.Duration, ByteSize, url.URL and time.Time are parsed natively.
+// The returned error is ValidationErrors listing every violation by dotted key path.
+func (kv KV) Bind(out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("aconfig: Bind requires a pointer to struct, got %T", out)
+	}
+
+	var errs ValidationErrors
+	bindStruct(&errs, kv, v.Elem(), "")
+
+	return errs.errorOrNil()
+}
+
+// BindCustomConfig binds the custom config into out, see KV.Bind
+func (a *Application) BindCustomConfig(out interface{}) error {
+	return a.GetCustomConfig().Bind(out)
+}
+
+func bindStruct(errs *ValidationErrors, m map[string]interface{}, v reflect.Value, path string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := jsonName(sf)
+		if name == "-" {
+			continue
+		}
+		if name == "" && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			bindStruct(errs, m, v.Field(i), path)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fieldPath := joinKey(path, name)
+		field := v.Field(i)
+
+		raw, ok := lookupFold(m, name)
+		if !ok || raw == nil {
+			raw, ok = sf.Tag.Lookup("default")
+		}
+		if !ok {
+			if required, _ := strconv.ParseBool(sf.Tag.Get("required")); required {
+				errs.add(fieldPath, "required")
+			} else if field.Kind() == reflect.Struct && !isNativeType(field.Type()) {
+				// Nested defaults and requirements still apply
+				bindStruct(errs, nil, field, fieldPath)
+			}
+			continue
+		}
+
+		if err := bindValue(errs, raw, field, fieldPath); err != nil {
+			errs.add(fieldPath, "%v", err)
+			continue
+		}
+		checkRange(errs, field, sf, fieldPath)
+	}
+}
+
+func lookupFold(m map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := m[name]; ok {
+		return value, true
+	}
+	for key, value := range m {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func isNativeType(t reflect.Type) bool {
+	return t == durationType || t == byteSizeType || t == urlType || t == timeType
+}
+
+// bindValue sets raw into v, the errors of nested values are added to errs.
+// v keeps its zero value when raw is nil, e.g. a null in a list.
+func bindValue(errs *ValidationErrors, raw interface{}, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := cast.ToDurationE(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case byteSizeType:
+		size, err := toByteSizeE(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(size))
+		return nil
+	case urlType:
+		u, err := url.Parse(cast.ToString(raw))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := bindValue(errs, raw, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(raw))
+		return nil
+	case reflect.Struct:
+		if v.Type() == timeType {
+			break
+		}
+		m, err := cast.ToStringMapE(raw)
+		if err != nil {
+			return err
+		}
+		bindStruct(errs, m, v, path)
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		m, err := cast.ToStringMapE(raw)
+		if err != nil {
+			return err
+		}
+		out := reflect.MakeMapWithSize(v.Type(), len(m))
+		for key, value := range m {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := bindValue(errs, value, elem, joinKey(path, key)); err != nil {
+				errs.add(joinKey(path, key), "%v", err)
+				continue
+			}
+			out.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		v.Set(out)
+		return nil
+	case reflect.Slice:
+		if s, ok := raw.(string); ok {
+			raw = strings.Split(s, ",")
+		}
+		items, err := cast.ToSliceE(raw)
+		if err != nil {
+			if strs, strErr := cast.ToStringSliceE(raw); strErr == nil {
+				items = make([]interface{}, len(strs))
+				for i := range strs {
+					items[i] = strs[i]
+				}
+			} else {
+				return err
+			}
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if err := bindValue(errs, item, out.Index(i), itemPath); err != nil {
+				errs.add(itemPath, "%v", err)
+			}
+		}
+		v.Set(out)
+		return nil
+	}
+
+	value, err := coerce(v.Type(), raw)
+	if err != nil {
+		return err
+	}
+	v.Set(value)
+
+	return nil
+}
+
+func checkRange(errs *ValidationErrors, v reflect.Value, sf reflect.StructField, path string) {
+	for _, bound := range []string{"min", "max"} {
+		tag, ok := sf.Tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+
+		var value, limit float64
+		var err error
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+			value = float64(v.Len())
+			limit, err = strconv.ParseFloat(tag, 64)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(v.Int())
+			if v.Type() == durationType {
+				var d time.Duration
+				d, err = time.ParseDuration(tag)
+				limit = float64(d)
+			} else if v.Type() == byteSizeType {
+				var size ByteSize
+				size, err = ParseByteSize(tag)
+				limit = float64(size)
+			} else {
+				limit, err = strconv.ParseFloat(tag, 64)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = float64(v.Uint())
+			limit, err = strconv.ParseFloat(tag, 64)
+		case reflect.Float32, reflect.Float64:
+			value = v.Float()
+			limit, err = strconv.ParseFloat(tag, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			errs.add(path, "invalid %s tag %q", bound, tag)
+			continue
+		}
+
+		if bound == "min" && value < limit {
+			errs.add(path, "must be at least %s", tag)
+		}
+		if bound == "max" && value > limit {
+			errs.add(path, "must be at most %s", tag)
+		}
+	}
+}
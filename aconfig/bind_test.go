@@ -0,0 +1,99 @@
+package aconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type bindTarget struct {
+	Name     string                 `json:"name" required:"true"`
+	Timeout  time.Duration          `json:"timeout" default:"5s"`
+	Size     ByteSize               `json:"size" default:"1KB"`
+	Replicas int                    `json:"replicas" min:"1" max:"3" default:"1"`
+	Tags     []string               `json:"tags"`
+	Values   []interface{}          `json:"values"`
+	Extra    map[string]interface{} `json:"extra"`
+	Limit    *int                   `json:"limit"`
+	Nested   struct {
+		Enabled bool `json:"enabled" default:"true"`
+	} `json:"nested"`
+}
+
+func TestKVBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		kv      KV
+		want    func(*bindTarget) bool
+		errPath []string
+	}{
+		{
+			name: "defaults",
+			kv:   KV{"name": "a"},
+			want: func(b *bindTarget) bool {
+				return b.Timeout == 5*time.Second && b.Size == KiloByte && b.Replicas == 1 && b.Nested.Enabled
+			},
+		},
+		{
+			name: "native types",
+			kv:   KV{"name": "a", "timeout": "1m", "size": "2MiB", "tags": "x,y"},
+			want: func(b *bindTarget) bool {
+				return b.Timeout == time.Minute && b.Size == 2*MegaByte && reflect.DeepEqual(b.Tags, []string{"x", "y"})
+			},
+		},
+		{
+			name: "null in list",
+			kv:   KV{"name": "a", "values": []interface{}{1, nil}},
+			want: func(b *bindTarget) bool {
+				return reflect.DeepEqual(b.Values, []interface{}{1, nil})
+			},
+		},
+		{
+			name: "null in map",
+			kv:   KV{"name": "a", "extra": map[string]interface{}{"k": nil}},
+			want: func(b *bindTarget) bool {
+				v, ok := b.Extra["k"]
+				return ok && v == nil
+			},
+		},
+		{
+			name: "null pointer",
+			kv:   KV{"name": "a", "limit": nil},
+			want: func(b *bindTarget) bool {
+				return b.Limit == nil
+			},
+		},
+		{
+			name:    "violations",
+			kv:      KV{"replicas": 5, "timeout": "soon"},
+			errPath: []string{"name", "timeout", "replicas"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bindTarget
+			err := tt.kv.Bind(&b)
+			if tt.errPath != nil {
+				var errs ValidationErrors
+				if !errors.As(err, &errs) {
+					t.Fatalf("err = %v, want ValidationErrors", err)
+				}
+				var paths []string
+				for _, e := range errs {
+					paths = append(paths, e.Path)
+				}
+				if !reflect.DeepEqual(paths, tt.errPath) {
+					t.Errorf("paths = %v, want %v", paths, tt.errPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !tt.want(&b) {
+				t.Errorf("unexpected %+v", b)
+			}
+		})
+	}
+}
@@ -1,14 +1,5 @@
 package aconfig
 
-import (
-	"encoding/json"
-	"io/ioutil"
-	"time"
-
-	"github.com/ghodss/yaml"
-	"github.com/spf13/cast"
-)
-
 type Interface struct {
 	Name string `json:"name,omitempty"`
 }
@@ -56,28 +47,23 @@ type Application struct {
 	TypeMeta   `json:",inline"`
 	ObjectMeta `json:"metadata,omitempty"`
 	Spec       ApplicationSpec `json:"spec,omitempty"`
+
+	envOverrides []EnvOverride
+	sources      map[string]string
+	secrets      map[string]bool
 }
 
 type PreProcessFunc func([]byte) ([]byte, error)
 
+type PostProcessFunc func(*Application) error
+
 func New(configFile string, funcs ...PreProcessFunc) (*Application, error) {
-	var application = &Application{}
-	data, err := ioutil.ReadFile(configFile)
-	if err != nil {
-		return nil, err
-	}
-	// Run the funcs on it
-	for _, f := range funcs {
-		if data, err = f(data); err != nil {
-			return nil, err
-		}
-	}
-	err = yaml.Unmarshal(data, application)
-	if err != nil {
-		return nil, err
+	loader := &Loader{
+		Files:           []string{configFile},
+		PreProcessFuncs: funcs,
 	}
 
-	return application, nil
+	return loader.Load()
 }
 
 func (a *Application) GetName() string {
@@ -124,86 +110,3 @@ func (a *Application) GetMatchedPrimaryPortLocation(name PortName) *Location {
 func (a *Application) GetCustomConfig() KV {
 	return a.Spec.CustomConfig
 }
-
-type KV map[string]interface{}
-
-func (kv KV) LoadTo(out interface{}) error {
-	jsonStr, err := json.Marshal(kv)
-	if err != nil {
-		return err
-	}
-
-	if err = json.Unmarshal(jsonStr, out); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (kv KV) get(key string) interface{} {
-	return kv[key]
-}
-
-func (kv KV) Get(key string) interface{} {
-	return kv.get(key)
-}
-
-func (kv KV) GetString(key string) string {
-	return cast.ToString(kv.get(key))
-}
-
-func (kv KV) GetBool(key string) bool {
-	return cast.ToBool(kv.get(key))
-}
-
-func (kv KV) GetDuration(key string) time.Duration {
-	return cast.ToDuration(kv.get(key))
-}
-
-func (kv KV) GetFloat64(key string) float64 {
-	return cast.ToFloat64(kv.get(key))
-}
-
-func (kv KV) GetInt(key string) int {
-	return cast.ToInt(kv.get(key))
-}
-
-func (kv KV) GetInt32(key string) int32 {
-	return cast.ToInt32(kv.get(key))
-}
-
-func (kv KV) GetInt64(key string) int64 {
-	return cast.ToInt64(kv.get(key))
-}
-
-func (kv KV) GetUint(key string) uint {
-	return cast.ToUint(kv.get(key))
-}
-
-func (kv KV) GetUint32(key string) uint32 {
-	return cast.ToUint32(kv.get(key))
-}
-
-func (kv KV) GetUint64(key string) uint64 {
-	return cast.ToUint64(kv.get(key))
-}
-
-func (kv KV) GetStringMap(key string) map[string]interface{} {
-	return cast.ToStringMap(kv.get(key))
-}
-
-func (kv KV) GetStringMapString(key string) map[string]string {
-	return cast.ToStringMapString(kv.get(key))
-}
-
-func (kv KV) GetStringMapStringSlice(key string) map[string][]string {
-	return cast.ToStringMapStringSlice(kv.get(key))
-}
-
-func (kv KV) GetStringSlice(key string) []string {
-	return cast.ToStringSlice(kv.get(key))
-}
-
-func (kv KV) GetTime(key string) time.Time {
-	return cast.ToTime(kv.get(key))
-}
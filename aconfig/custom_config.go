@@ -1,9 +1,20 @@
 package aconfig
 
-import "strings"
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/alphaframework/alpha/alog"
+	"github.com/alphaframework/alpha/autil"
+)
 
 const (
 	defaultLogLevel            = "info"
+	defaultLogFormat           = "console"
 	defaultLogDirectory        = "/data/log"
 	defaultLogMaxSize          = 512 // MB
 	defaultLogMaxAge           = 240 // day
@@ -19,21 +30,83 @@ const (
 	defaultConnectionMaxLifeSeconds  = 3600 // an hour
 	defaultConnectionMaxIdleSeconds  = 300  // 5 minutes
 	defaultSlowThresholdMilliseconds = 500  // 0.5 second
+
+	// DefaultCommonPath is the path of the Common section in the custom config
+	DefaultCommonPath = "common"
 )
 
+var logFormats = []string{"console", "json"}
+
 type Common struct {
 	Log       Log       `json:"log,omitempty"`
 	Database  Database  `json:"database,omitempty"`
 	Encryptor Encryptor `json:"encryptor,omitempty"`
 	Var       Var       `json:"var,omitempty"`
+
+	applicationName string
+}
+
+// NewCommon decodes kv into a Common, completes and validates it
+func NewCommon(kv KV, applicationName string) (*Common, error) {
+	c := &Common{}
+	if err := kv.LoadTo(c); err != nil {
+		return nil, fmt.Errorf("aconfig: common: %v", err)
+	}
+	c.Complete(applicationName)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadCommon loads the Common section at path of the custom config, DefaultCommonPath if empty
+func LoadCommon(a *Application, path string) (*Common, error) {
+	if path == "" {
+		path = DefaultCommonPath
+	}
+
+	return NewCommon(a.GetCustomConfig().Sub(path), a.GetName())
 }
 
 func (c *Common) Complete(applicationName string) {
+	c.applicationName = applicationName
 	c.Log.complete(applicationName)
 	c.Database.complete()
+	c.Encryptor.complete()
 	c.Var.complete(applicationName)
 }
 
+// Validate checks a completed Common, the returned error is ValidationErrors
+func (c *Common) Validate() error {
+	var errs ValidationErrors
+	c.Log.validate(&errs)
+	c.Database.validate(&errs)
+	c.Encryptor.validate(&errs)
+
+	return errs.errorOrNil()
+}
+
+// LogConfig converts the Log into the alog.InitLogger config, the unset sizes and compression take their defaults
+func (c *Common) LogConfig() *alog.Config {
+	log := c.Log
+	log.completeRotation()
+
+	return &alog.Config{
+		ApplicationName:  c.applicationName,
+		Directory:        log.Directory,
+		Level:            log.Level,
+		Format:           log.Format,
+		MaxSize:          *log.MaxSize,
+		MaxAge:           *log.MaxAge,
+		MaxBackups:       *log.MaxBackups,
+		Compress:         *log.Compress,
+		BackupTimeFormat: log.BackupTimeFormat,
+		Sinks:            log.Sinks,
+		Cumulative:       log.Cumulative,
+	}
+}
+
 type Var struct {
 	TmpDirectory     string `json:"tmp_directory,omitempty"`
 	PrivateDirectory string `json:"private_directory,omitempty"`
@@ -67,17 +140,31 @@ type Log struct {
 	MaxBackups       *int   `json:"max_backups,omitempty"`
 	Compress         *bool  `json:"compress,omitempty"`
 	BackupTimeFormat string `json:"backup_time_format,omitempty"`
+	// Sinks route the levels to files, stdout or stderr, a file per level if empty
+	Sinks []alog.Sink `json:"sinks,omitempty"`
+	// Cumulative makes the file of a level also receive the levels above it, without Sinks only
+	Cumulative bool `json:"cumulative,omitempty"`
 }
 
 func (l *Log) complete(applicationName string) {
 	if l.Level == "" {
 		l.Level = defaultLogLevel
 	}
+	if l.Format == "" {
+		l.Format = defaultLogFormat
+	}
 	if l.Directory == "" {
 		l.Directory = defaultLogDirectory
 	}
 	l.Directory = joinPath(l.Directory, applicationName)
+	l.completeRotation()
+	if l.BackupTimeFormat == "" {
+		l.BackupTimeFormat = defaultLogBackupTimeFormat
+	}
+}
 
+// completeRotation defaults the sizes and compression of the rotated files
+func (l *Log) completeRotation() {
 	if l.MaxSize == nil {
 		maxSize := defaultLogMaxSize
 		l.MaxSize = &maxSize
@@ -91,11 +178,38 @@ func (l *Log) complete(applicationName string) {
 		l.MaxBackups = &maxBackups
 	}
 	if l.Compress == nil {
-		compress := true
+		compress := defaultLogCompress
 		l.Compress = &compress
 	}
-	if l.BackupTimeFormat == "" {
-		l.BackupTimeFormat = defaultLogBackupTimeFormat
+}
+
+func (l *Log) validate(errs *ValidationErrors) {
+	var level zapcore.Level
+	if err := level.Set(l.Level); err != nil {
+		errs.add("log.level", "%q is not a log level", l.Level)
+	}
+	if !autil.In(l.Format, logFormats) {
+		errs.add("log.format", "%q is not in %v", l.Format, logFormats)
+	}
+	if l.MaxSize != nil && *l.MaxSize <= 0 {
+		errs.add("log.max_size", "must be positive")
+	}
+	if l.MaxAge != nil && *l.MaxAge < 0 {
+		errs.add("log.max_age", "must not be negative")
+	}
+	if l.MaxBackups != nil && *l.MaxBackups < 0 {
+		errs.add("log.max_backups", "must not be negative")
+	}
+	// A valid layout formats the reference time into itself and depends on the time
+	reference := time.Date(2006, 01, 02, 15, 04, 05, 0, time.UTC)
+	if l.BackupTimeFormat != reference.Format(l.BackupTimeFormat) ||
+		l.BackupTimeFormat == reference.AddDate(1, 1, 1).Format(l.BackupTimeFormat) {
+		errs.add("log.backup_time_format", "%q is not a valid time layout", l.BackupTimeFormat)
+	}
+	for i := range l.Sinks {
+		if err := l.Sinks[i].Validate(); err != nil {
+			errs.add(fmt.Sprintf("log.sinks[%d]", i), "%v", err)
+		}
 	}
 }
 
@@ -129,6 +243,49 @@ func (db *Database) complete() {
 	}
 }
 
+func (db *Database) validate(errs *ValidationErrors) {
+	if db.MaxOpenConnections <= 0 {
+		errs.add("database.max_open_connections", "must be positive")
+	}
+	if db.MaxIdleConnections <= 0 {
+		errs.add("database.max_idle_connections", "must be positive")
+	}
+	if db.ConnectionMaxLifeSeconds <= 0 {
+		errs.add("database.connection_max_life_seconds", "must be positive")
+	}
+	if db.ConnectionMaxIdleSeconds <= 0 {
+		errs.add("database.connection_max_idle_seconds", "must be positive")
+	}
+	if db.SlowThresholdMilliseconds <= 0 {
+		errs.add("database.slow_threshold_milliseconds", "must be positive")
+	}
+}
+
+func (db *Database) SlowThreshold() time.Duration {
+	return time.Duration(db.SlowThresholdMilliseconds) * time.Millisecond
+}
+
+// ApplyTo sets the connection pool settings on stdDB
+func (db *Database) ApplyTo(stdDB *sql.DB) {
+	stdDB.SetMaxOpenConns(db.MaxOpenConnections)
+	stdDB.SetMaxIdleConns(db.MaxIdleConnections)
+	stdDB.SetConnMaxLifetime(time.Duration(db.ConnectionMaxLifeSeconds) * time.Second)
+	stdDB.SetConnMaxIdleTime(time.Duration(db.ConnectionMaxIdleSeconds) * time.Second)
+}
+
 type Encryptor struct {
-	S string `json:"s,omitempty"`
+	S         string `json:"s,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+func (e *Encryptor) complete() {
+	if e.Algorithm == "" {
+		e.Algorithm = defaultEncryptorAlgorithm
+	}
+}
+
+func (e *Encryptor) validate(errs *ValidationErrors) {
+	if e.Algorithm != EncryptorAlgorithmPBEWithMD5AndDES {
+		errs.add("encryptor.algorithm", "%q not supported", e.Algorithm)
+	}
 }
@@ -0,0 +1,23 @@
+package aconfig
+
+import "testing"
+
+func TestCommonLogConfig(t *testing.T) {
+	var c Common
+	config := c.LogConfig()
+	if config.MaxSize != defaultLogMaxSize || config.MaxAge != defaultLogMaxAge ||
+		config.MaxBackups != defaultLogMaxBackups || config.Compress != defaultLogCompress {
+		t.Errorf("incomplete Common: %+v", config)
+	}
+	if c.Log.MaxSize != nil || c.Log.Compress != nil {
+		t.Errorf("LogConfig() completed the Log of the Common: %+v", c.Log)
+	}
+
+	maxSize, compress := 1, false
+	c = Common{Log: Log{MaxSize: &maxSize, Compress: &compress}}
+	c.Complete("demo")
+	config = c.LogConfig()
+	if config.MaxSize != 1 || config.Compress || config.ApplicationName != "demo" || config.Directory != "/data/log/demo" {
+		t.Errorf("completed Common: %+v", config)
+	}
+}
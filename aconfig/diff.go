@@ -0,0 +1,97 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a difference between two Applications at the dotted Path
+type Change struct {
+	Path string      `json:"path"`
+	Type ChangeType  `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Diff compares the json trees of two Applications, maps are compared key by key and
+// other values as a whole. The values matching redactor or reported as secrets are redacted,
+// redactor may be nil to keep every value.
+func Diff(oldApp, newApp *Application, redactor *Redactor) ([]Change, error) {
+	oldTree, err := toTree(oldApp)
+	if err != nil {
+		return nil, err
+	}
+	newTree, err := toTree(newApp)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	diffValues(&changes, "", oldTree, newTree)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	if redactor != nil {
+		isSecret := func(path string) bool {
+			return oldApp.IsSecret(path) || newApp.IsSecret(path)
+		}
+		for i := range changes {
+			c := &changes[i]
+			if c.Old != nil {
+				c.Old = redactor.redact(c.Old, c.Path, isSecret)
+			}
+			if c.New != nil {
+				c.New = redactor.redact(c.New, c.Path, isSecret)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func toTree(a *Application) (interface{}, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err = json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+func diffValues(changes *[]Change, path string, oldValue, newValue interface{}) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		for k, ov := range oldMap {
+			if nv, ok := newMap[k]; ok {
+				diffValues(changes, joinKey(path, k), ov, nv)
+			} else {
+				*changes = append(*changes, Change{Path: joinKey(path, k), Type: ChangeRemoved, Old: ov})
+			}
+		}
+		for k, nv := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				*changes = append(*changes, Change{Path: joinKey(path, k), Type: ChangeAdded, New: nv})
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{Path: path, Type: ChangeModified, Old: oldValue, New: newValue})
+	}
+}
@@ -0,0 +1,86 @@
+package aconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *Application)
+		want   []Change
+	}{
+		{name: "equal", modify: func(a *Application) {}},
+		{
+			name: "modified",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location.Port = 3307
+			},
+			want: []Change{{Path: "spec.secondary_ports.mysql.matched_primary_port.location.port", Type: ChangeModified, Old: 3306.0, New: 3307.0}},
+		},
+		{
+			name: "added and removed",
+			modify: func(a *Application) {
+				delete(a.Spec.PrimaryPorts, "http")
+				a.Spec.SecondaryPorts["mysql"].Options["charset"] = "utf8mb4"
+			},
+			want: []Change{
+				{Path: "spec.primary_ports", Type: ChangeRemoved, Old: map[string]interface{}{"http": map[string]interface{}{
+					"interface": map[string]interface{}{"name": "http"},
+					"location":  map[string]interface{}{"address": "0.0.0.0", "port": 8080.0},
+				}}},
+				{Path: "spec.secondary_ports.mysql.options.charset", Type: ChangeAdded, New: "utf8mb4"},
+			},
+		},
+		{
+			name: "lists as a whole",
+			modify: func(a *Application) {
+				a.Spec.CustomConfig = KV{"hosts": []interface{}{"a", "c"}}
+			},
+			want: []Change{{Path: "spec.custom_config", Type: ChangeAdded, New: map[string]interface{}{"hosts": []interface{}{"a", "c"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newApp := testApplication()
+			tt.modify(newApp)
+			changes, err := Diff(testApplication(), newApp, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("Diff() = %v, want %v", changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRedacts(t *testing.T) {
+	oldApp, newApp := testApplication(), testApplication()
+	oldApp.Spec.SecondaryPorts["mysql"].Options["password"] = "old"
+	newApp.Spec.SecondaryPorts["mysql"].Options["password"] = "new"
+	oldApp.Spec.CustomConfig = KV{"api": map[string]interface{}{"key": "k1", "url": "u1"}}
+	newApp.Spec.CustomConfig = KV{"api": map[string]interface{}{"key": "k2", "url": "u2"}}
+	newApp.secrets = map[string]bool{"spec.custom_config.api.key": true}
+	newApp.Spec.CustomConfig["auth"] = map[string]interface{}{"token": "t", "user": "u"}
+
+	redactor, err := NewRedactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Diff(oldApp, newApp, redactor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{
+		{Path: "spec.custom_config.api.key", Type: ChangeModified, Old: RedactedValue, New: RedactedValue},
+		{Path: "spec.custom_config.api.url", Type: ChangeModified, Old: "u1", New: "u2"},
+		{Path: "spec.custom_config.auth", Type: ChangeAdded, New: map[string]interface{}{"token": RedactedValue, "user": "u"}},
+		{Path: "spec.secondary_ports.mysql.options.password", Type: ChangeModified, Old: RedactedValue, New: RedactedValue},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Diff() = %v, want %v", changes, want)
+	}
+}
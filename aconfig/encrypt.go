@@ -0,0 +1,148 @@
+package aconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alphaframework/alpha/autil/acrypto/pbe"
+)
+
+const (
+	EncryptorAlgorithmPBEWithMD5AndDES = "PBEWithMD5AndDES"
+
+	defaultEncryptorAlgorithm = EncryptorAlgorithmPBEWithMD5AndDES
+
+	encryptedPrefix = "ENC("
+	encryptedSuffix = ")"
+)
+
+func (e *Encryptor) algorithm() string {
+	if e.Algorithm == "" {
+		return defaultEncryptorAlgorithm
+	}
+
+	return e.Algorithm
+}
+
+func (e *Encryptor) Encrypt(plaintext string) (string, error) {
+	if e.S == "" {
+		return "", fmt.Errorf("encryptor/s is required")
+	}
+	switch e.algorithm() {
+	case EncryptorAlgorithmPBEWithMD5AndDES:
+		return pbe.PBEWithMD5AndDES_Encrypt(plaintext, e.S)
+	default:
+		return "", fmt.Errorf("encryptor/algorithm %q not supported", e.Algorithm)
+	}
+}
+
+func (e *Encryptor) Decrypt(ciphertext string) (string, error) {
+	if e.S == "" {
+		return "", fmt.Errorf("encryptor/s is required")
+	}
+	switch e.algorithm() {
+	case EncryptorAlgorithmPBEWithMD5AndDES:
+		return pbe.PBEWithMD5AndDES_Decrypt(ciphertext, e.S)
+	default:
+		return "", fmt.Errorf("encryptor/algorithm %q not supported", e.Algorithm)
+	}
+}
+
+// EncryptValue encrypts plaintext into the ENC(...) marker syntax
+func (e *Encryptor) EncryptValue(plaintext string) (string, error) {
+	ciphertext, err := e.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + ciphertext + encryptedSuffix, nil
+}
+
+func isEncrypted(s string) bool {
+	return strings.HasPrefix(s, encryptedPrefix) && strings.HasSuffix(s, encryptedSuffix)
+}
+
+// Decrypter returns a PostProcessFunc decrypting the ENC(...) values of the Application
+func Decrypter(e *Encryptor) PostProcessFunc {
+	return func(a *Application) error {
+		return a.Decrypt(e)
+	}
+}
+
+// Decrypt replaces the ENC(...) values of the secondary port options and the custom config
+// by their plaintext, the decrypted paths are reported as secrets
+func (a *Application) Decrypt(e *Encryptor) error {
+	return a.resolveValues(func(path, s string) (string, bool, error) {
+		if !isEncrypted(s) {
+			return s, false, nil
+		}
+		plaintext, err := e.Decrypt(strings.TrimSuffix(strings.TrimPrefix(s, encryptedPrefix), encryptedSuffix))
+		if err != nil {
+			return "", false, fmt.Errorf("aconfig: decrypt %s: %v", path, err)
+		}
+
+		return plaintext, true, nil
+	})
+}
+
+// IsSecret reports whether the value at path was decrypted or resolved from a secret
+func (a *Application) IsSecret(path string) bool {
+	return a.secrets[path]
+}
+
+// resolveValues replaces the string values of the secondary port options and the custom config by fn,
+// the replaced paths are marked as secrets
+func (a *Application) resolveValues(fn func(path, s string) (string, bool, error)) error {
+	for _, name := range a.secondaryPortNames() {
+		sp := a.Spec.SecondaryPorts[name]
+		if err := a.resolveKV(sp.Options, "spec.secondary_ports."+string(name)+".options", fn); err != nil {
+			return err
+		}
+	}
+
+	return a.resolveKV(a.Spec.CustomConfig, "spec.custom_config", fn)
+}
+
+func (a *Application) resolveKV(kv map[string]interface{}, path string, fn func(path, s string) (string, bool, error)) error {
+	for k, v := range kv {
+		value, err := a.resolveValue(v, joinKey(path, k), fn)
+		if err != nil {
+			return err
+		}
+		kv[k] = value
+	}
+
+	return nil
+}
+
+func (a *Application) resolveValue(v interface{}, path string, fn func(path, s string) (string, bool, error)) (interface{}, error) {
+	switch value := v.(type) {
+	case string:
+		s, replaced, err := fn(path, value)
+		if err != nil {
+			return nil, err
+		}
+		if replaced {
+			if a.secrets == nil {
+				a.secrets = map[string]bool{}
+			}
+			a.secrets[path] = true
+		}
+		return s, nil
+	case map[string]interface{}:
+		return value, a.resolveKV(value, path, fn)
+	case KV:
+		return value, a.resolveKV(value, path, fn)
+	case []interface{}:
+		for i, elem := range value {
+			resolved, err := a.resolveValue(elem, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = resolved
+		}
+		return value, nil
+	}
+
+	return v, nil
+}
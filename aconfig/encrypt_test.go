@@ -0,0 +1,68 @@
+package aconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDecryptsEncryptedValues(t *testing.T) {
+	encryptor := &Encryptor{S: "test-secret"}
+	password, err := encryptor.EncryptValue("p@ssw0rd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := encryptor.EncryptValue("t0ken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := strings.Replace(validManifest, "        database: demo\n", "        database: demo\n        password: "+password+"\n", 1) +
+		"  custom_config:\n    tokens:\n    - " + token + "\n    plain: ENC-free\n"
+
+	loader := &Loader{
+		PostProcessFuncs:       []PostProcessFunc{Decrypter(encryptor)},
+		DisableEnvOverlay:      true,
+		DisableSecretResolving: true,
+	}
+	application, err := loader.LoadData("app.yaml", []byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		value  string
+		secret bool
+	}{
+		{path: "spec.secondary_ports.mysql.options.password", value: "p@ssw0rd", secret: true},
+		{path: "spec.custom_config.tokens[0]", value: "t0ken", secret: true},
+		{path: "spec.custom_config.plain", value: "ENC-free", secret: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got string
+			if strings.HasPrefix(tt.path, "spec.custom_config.") {
+				got = application.Spec.CustomConfig.GetString(strings.TrimPrefix(tt.path, "spec.custom_config."))
+			} else {
+				got = application.Spec.SecondaryPorts["mysql"].Options.GetString("password")
+			}
+			if got != tt.value {
+				t.Errorf("value = %q, want %q", got, tt.value)
+			}
+			if application.IsSecret(tt.path) != tt.secret {
+				t.Errorf("IsSecret() = %v, want %v", !tt.secret, tt.secret)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUndecryptableValues(t *testing.T) {
+	loader := &Loader{
+		PostProcessFuncs:       []PostProcessFunc{Decrypter(&Encryptor{S: "test-secret"})},
+		DisableEnvOverlay:      true,
+		DisableSecretResolving: true,
+	}
+	manifest := strings.Replace(validManifest, "        database: demo\n", "        database: demo\n        password: ENC(not base64!)\n", 1)
+	if _, err := loader.LoadData("app.yaml", []byte(manifest)); err == nil || !strings.Contains(err.Error(), "options.password") {
+		t.Errorf("LoadData() error = %v, want a decrypt error of the password", err)
+	}
+}
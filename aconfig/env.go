@@ -0,0 +1,305 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	// DefaultEnvPrefix is the prefix of the environment variables overlaid by New,
+	// e.g. ALPHA_SPEC__SECONDARY_PORTS__MYSQL__OPTIONS__PASSWORD
+	DefaultEnvPrefix = "ALPHA_"
+
+	envPathSeparator = "__"
+)
+
+// EnvOverride reports an environment variable applied onto the Application
+type EnvOverride struct {
+	Env  string `json:"env"`
+	Path string `json:"path"`
+}
+
+// OverlayEnv maps the environment variables starting with prefix onto the Application.
+// The rest of the variable name is lowercased and split by "__" into a path of json field names and map keys.
+// Variables which do not match any field are ignored.
+func (a *Application) OverlayEnv(prefix string) ([]EnvOverride, error) {
+	return a.overlayEnv(prefix, os.Environ())
+}
+
+func (a *Application) overlayEnv(prefix string, environ []string) ([]EnvOverride, error) {
+	sort.Strings(environ)
+
+	var overrides []EnvOverride
+	for _, env := range environ {
+		i := strings.Index(env, "=")
+		if i < 0 {
+			continue
+		}
+		name, value := env[:i], env[i+1:]
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(name, prefix)), envPathSeparator)
+		var resolved []string
+		ok, err := setPath(reflect.ValueOf(a).Elem(), path, value, &resolved)
+		if err != nil {
+			return nil, fmt.Errorf("aconfig: env %s: %v", name, err)
+		}
+		if !ok {
+			continue
+		}
+		override := EnvOverride{Env: name, Path: strings.Join(resolved, ".")}
+		overrides = append(overrides, override)
+		a.setSource(override.Path, "env:"+name)
+	}
+	a.envOverrides = append(a.envOverrides, overrides...)
+
+	return overrides, nil
+}
+
+// EnvOverrides returns the environment variables applied by New
+func (a *Application) EnvOverrides() []EnvOverride {
+	return a.envOverrides
+}
+
+// setPath walks v along path and sets the value it leads to from raw.
+// It returns false if the path does not match any field.
+func setPath(v reflect.Value, path []string, raw string, resolved *[]string) (bool, error) {
+	if len(path) > 0 && path[0] == "" {
+		return false, fmt.Errorf("empty path segment")
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return setPath(v.Elem(), path, raw, resolved)
+		}
+		nv := reflect.New(v.Type().Elem())
+		ok, err := setPath(nv.Elem(), path, raw, resolved)
+		if ok && err == nil {
+			v.Set(nv)
+		}
+		return ok, err
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			break
+		}
+		if len(path) == 0 {
+			// A variable such as ALPHA_SPEC does not address a value
+			return false, nil
+		}
+		f, name, ok := fieldByJSONName(v, path[0])
+		if !ok {
+			return false, nil
+		}
+		*resolved = append(*resolved, name)
+		return setPath(f, path[1:], raw, resolved)
+	case reflect.Map:
+		if len(path) == 0 {
+			return false, nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return false, fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		key := mapKeyFold(v, path[0])
+		*resolved = append(*resolved, key.String())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		ok, err := setPath(elem, path[1:], raw, resolved)
+		if !ok || err != nil {
+			return ok, err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(key, elem)
+		return true, nil
+	case reflect.Interface:
+		if len(path) == 0 {
+			value, err := coerceLike(v.Elem(), raw)
+			if err != nil {
+				return false, err
+			}
+			v.Set(value)
+			return true, nil
+		}
+		// Free-form values such as KV are nested maps
+		m, ok := v.Interface().(map[string]interface{})
+		if !ok {
+			if kv, isKV := v.Interface().(KV); isKV {
+				m = kv
+			} else {
+				m = map[string]interface{}{}
+			}
+		}
+		mv := reflect.ValueOf(&m).Elem()
+		if ok, err := setPath(mv, path, raw, resolved); !ok || err != nil {
+			return ok, err
+		}
+		v.Set(mv)
+		return true, nil
+	}
+
+	if len(path) > 0 {
+		return false, nil
+	}
+
+	value, err := coerce(v.Type(), raw)
+	if err != nil {
+		return false, err
+	}
+	v.Set(value)
+
+	return true, nil
+}
+
+// fieldByJSONName finds the struct field named name (case-insensitively) in its json tag,
+// looking into embedded structs such as TypeMeta
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, string, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tagName := jsonName(sf)
+		if tagName == "-" {
+			continue
+		}
+		if tagName == "" && sf.Anonymous {
+			if f, n, ok := fieldByJSONName(v.Field(i), name); ok {
+				return f, n, true
+			}
+			continue
+		}
+		if tagName == "" {
+			tagName = sf.Name
+		}
+		if strings.EqualFold(tagName, name) {
+			return v.Field(i), tagName, true
+		}
+	}
+
+	return reflect.Value{}, "", false
+}
+
+func jsonName(sf reflect.StructField) string {
+	tag := sf.Tag.Get("json")
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+
+	return tag
+}
+
+// mapKeyFold returns the existing key of m matching name case-insensitively, or name itself
+func mapKeyFold(m reflect.Value, name string) reflect.Value {
+	for _, key := range m.MapKeys() {
+		if strings.EqualFold(key.String(), name) {
+			return key
+		}
+	}
+
+	return reflect.ValueOf(name).Convert(m.Type().Key())
+}
+
+// coerceLike converts raw to the type of the existing free-form value, or keeps it as a string
+func coerceLike(existing reflect.Value, raw string) (reflect.Value, error) {
+	if !existing.IsValid() {
+		return reflect.ValueOf(raw), nil
+	}
+	switch existing.Kind() {
+	case reflect.Map, reflect.Slice:
+		var out interface{}
+		if err := json.Unmarshal([]byte(raw), &out); err != nil {
+			return reflect.Value{}, fmt.Errorf("unable to cast %q to %s: %v", raw, existing.Type(), err)
+		}
+		return reflect.ValueOf(out), nil
+	}
+
+	return coerce(existing.Type(), raw)
+}
+
+// coerce converts raw to t the same way as the KV.GetXxx helpers
+func coerce(t reflect.Type, raw interface{}) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		d, err := cast.ToDurationE(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		tm, err := cast.ToTimeE(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		str, err := cast.ToStringE(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetString(str)
+	case reflect.Bool:
+		b, err := cast.ToBoolE(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := cast.ToInt64E(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %s", raw, t)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := cast.ToUint64E(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowUint(u) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %s", raw, t)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := cast.ToFloat64E(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+		}
+		s, err := cast.ToStringSliceE(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(reflect.ValueOf(s).Convert(t))
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+
+	return v, nil
+}
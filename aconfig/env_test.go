@@ -0,0 +1,56 @@
+package aconfig
+
+import (
+	"testing"
+)
+
+func TestOverlayEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		path  string
+		check func(*Application) bool
+	}{
+		{"struct", "ALPHA_SPEC=x", "", nil},
+		{"metadata", "ALPHA_METADATA={}", "", nil},
+		{"map", "ALPHA_SPEC__SECONDARY_PORTS=x", "", nil},
+		{"unknown field", "ALPHA_SPEC__UNKNOWN=x", "", nil},
+		{"field", "ALPHA_METADATA__NAME=demo", "metadata.name", func(a *Application) bool {
+			return a.Name == "demo"
+		}},
+		{"port", "ALPHA_SPEC__PRIMARY_PORTS__HTTP__LOCATION__PORT=8080", "spec.primary_ports.http.location.port", func(a *Application) bool {
+			return a.Spec.PrimaryPorts["http"].Location.Port == 8080
+		}},
+		{"custom config", "ALPHA_SPEC__CUSTOM_CONFIG__CACHE__TTL=5", "spec.custom_config.cache.ttl", func(a *Application) bool {
+			return a.GetCustomConfig().GetInt("cache.ttl") == 5
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Application{}
+			overrides, err := a.overlayEnv(DefaultEnvPrefix, []string{tt.env, "OTHER=1"})
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if tt.path == "" {
+				if len(overrides) != 0 {
+					t.Errorf("overrides = %v, want none", overrides)
+				}
+				return
+			}
+			if len(overrides) != 1 || overrides[0].Path != tt.path {
+				t.Fatalf("overrides = %v, want %s", overrides, tt.path)
+			}
+			if !tt.check(a) {
+				t.Errorf("unexpected %+v", a)
+			}
+		})
+	}
+}
+
+func TestOverlayEnvInvalidValue(t *testing.T) {
+	a := &Application{}
+	if _, err := a.overlayEnv(DefaultEnvPrefix, []string{"ALPHA_SPEC__PRIMARY_PORTS__HTTP__LOCATION__PORT=http"}); err == nil {
+		t.Errorf("a non numeric port was accepted")
+	}
+}
@@ -0,0 +1,109 @@
+package aconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// tomlLineRegexp matches a TOML table header or key/value line
+var tomlLineRegexp = regexp.MustCompile(`^(\[\[?[A-Za-z0-9_."' -]+\]\]?|[A-Za-z0-9_."'-]+\s*=)`)
+
+// NewFromBytes loads an Application from data, its format is detected if empty
+func NewFromBytes(data []byte, format Format, funcs ...PreProcessFunc) (*Application, error) {
+	loader := &Loader{
+		Format:          format,
+		PreProcessFuncs: funcs,
+	}
+
+	return loader.LoadData("<bytes>", data)
+}
+
+// NewFromReader loads an Application from r, its format is detected if empty
+func NewFromReader(r io.Reader, format Format, funcs ...PreProcessFunc) (*Application, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	loader := &Loader{
+		Format:          format,
+		PreProcessFuncs: funcs,
+	}
+
+	return loader.LoadData("<reader>", data)
+}
+
+// DetectFormat detects the format by the extension of name, or by sniffing data
+func DetectFormat(name string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			return FormatJSON
+		}
+		if tomlLineRegexp.MatchString(line) {
+			return FormatTOML
+		}
+		break
+	}
+
+	return FormatYAML
+}
+
+func unmarshalTree(data []byte, format Format) (map[string]interface{}, error) {
+	var jsonData []byte
+	var err error
+	switch format {
+	case FormatYAML:
+		jsonData, err = yaml.YAMLToJSON(data)
+	case FormatJSON:
+		jsonData = data
+	case FormatTOML:
+		var tree map[string]interface{}
+		if _, err = toml.Decode(string(data), &tree); err != nil {
+			return nil, err
+		}
+		jsonData, err = json.Marshal(tree)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	if err = decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
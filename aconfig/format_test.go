@@ -0,0 +1,128 @@
+package aconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tomlManifest = `# demo
+kind = "Application"
+api_version = "v1"
+
+[metadata]
+name = "demo"
+
+[spec.secondary_ports.mysql.interface]
+name = "mysql"
+
+[spec.secondary_ports.mysql.options]
+user = "root"
+database = "demo"
+
+[spec.secondary_ports.mysql.matched_primary_port.location]
+address = "127.0.0.1"
+`
+
+const jsonManifest = `{
+  "kind": "Application",
+  "api_version": "v1",
+  "metadata": {"name": "demo"},
+  "spec": {"secondary_ports": {"mysql": {
+    "interface": {"name": "mysql"},
+    "options": {"user": "root", "database": "demo"},
+    "matched_primary_port": {"location": {"address": "127.0.0.1"}}
+  }}}
+}`
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want Format
+	}{
+		{name: "yaml extension", file: "app.yaml", data: tomlManifest, want: FormatYAML},
+		{name: "yml extension", file: "APP.YML", want: FormatYAML},
+		{name: "json extension", file: "app.json", want: FormatJSON},
+		{name: "toml extension", file: "app.toml", want: FormatTOML},
+		{name: "yaml content", data: validManifest, want: FormatYAML},
+		{name: "json content", data: jsonManifest, want: FormatJSON},
+		{name: "toml content", data: tomlManifest, want: FormatTOML},
+		{name: "toml table first", data: "\n[metadata]\nname = \"demo\"\n", want: FormatTOML},
+		{name: "toml array of tables", data: "[[items]]\nname = \"a\"\n", want: FormatTOML},
+		{name: "yaml list", data: "# comment\n- a\n- b\n", want: FormatYAML},
+		{name: "yaml with equal sign in a value", data: "dsn: user=root\n", want: FormatYAML},
+		{name: "empty", data: "", want: FormatYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.file, []byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromBytesFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format Format
+	}{
+		{name: "yaml", data: validManifest},
+		{name: "json", data: jsonManifest},
+		{name: "toml", data: tomlManifest},
+		{name: "explicit toml", data: tomlManifest, format: FormatTOML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application, err := NewFromBytes([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = application.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := application.Spec.SecondaryPorts["mysql"].Options.GetString("database"); got != "demo" {
+				t.Errorf("database = %q, want demo", got)
+			}
+		})
+	}
+
+	if _, err := NewFromBytes([]byte(validManifest), "ini"); err == nil || !strings.Contains(err.Error(), `unsupported format "ini"`) {
+		t.Errorf("NewFromBytes() error = %v, want the unsupported format", err)
+	}
+}
+
+func TestLoadTOMLLayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := filepath.Join(dir, "app.yaml")
+	override := filepath.Join(dir, "app.prod.toml")
+	if err = ioutil.WriteFile(base, []byte(validManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(override, []byte("[spec.secondary_ports.mysql.matched_primary_port.location]\nport = 3307\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := &Loader{Files: []string{base, override}, Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+	application, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := application.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location
+	if location.Address != "127.0.0.1" || location.Port != 3307 {
+		t.Errorf("location = %+v, want 127.0.0.1:3307", location)
+	}
+	if got := application.Source("spec.secondary_ports.mysql.matched_primary_port.location.port"); got != override {
+		t.Errorf("source = %q, want %q", got, override)
+	}
+}
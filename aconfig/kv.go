@@ -0,0 +1,632 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyError is returned by the KV accessors for the path they failed on
+type KeyError struct {
+	Path string
+	Err  error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("aconfig: key %q: %v", e.Path, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// KV is a free-form config tree. The accessors take a path such as a.b[0].c,
+// a top-level key containing dots is matched first.
+type KV map[string]interface{}
+
+func (kv KV) LoadTo(out interface{}) error {
+	jsonStr, err := json.Marshal(kv)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(jsonStr, out); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits a.b[0].c into a, b, [0] and c
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	var key strings.Builder
+	flushKey := func() {
+		if key.Len() > 0 {
+			segments = append(segments, pathSegment{key: key.String()})
+			key.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '.':
+			if key.Len() == 0 && (i == 0 || path[i-1] != ']') {
+				return nil, fmt.Errorf("empty key in path")
+			}
+			flushKey()
+		case '[':
+			flushKey()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in path")
+			}
+			index, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in path", path[i+1:i+end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			i += end
+		default:
+			key.WriteByte(c)
+		}
+	}
+	flushKey()
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	return segments, nil
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case KV:
+		return m, true
+	}
+
+	return nil, false
+}
+
+func (kv KV) lookup(path string) (interface{}, error) {
+	if value, ok := kv[path]; ok {
+		return value, nil
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, &KeyError{Path: path, Err: err}
+	}
+
+	var cur interface{} = map[string]interface{}(kv)
+	for _, segment := range segments {
+		if segment.isIndex {
+			s, ok := cur.([]interface{})
+			if !ok || segment.index >= len(s) {
+				return nil, &KeyError{Path: path, Err: ErrKeyNotFound}
+			}
+			cur = s[segment.index]
+			continue
+		}
+		m, ok := asMap(cur)
+		if !ok {
+			return nil, &KeyError{Path: path, Err: ErrKeyNotFound}
+		}
+		if cur, ok = m[segment.key]; !ok {
+			return nil, &KeyError{Path: path, Err: ErrKeyNotFound}
+		}
+	}
+
+	return cur, nil
+}
+
+func (kv KV) get(key string) interface{} {
+	value, _ := kv.lookup(key)
+	return value
+}
+
+func (kv KV) Get(key string) interface{} {
+	return kv.get(key)
+}
+
+// GetE returns the value at path or a KeyError wrapping ErrKeyNotFound
+func (kv KV) GetE(path string) (interface{}, error) {
+	return kv.lookup(path)
+}
+
+func (kv KV) GetOrDefault(path string, defaultValue interface{}) interface{} {
+	value, err := kv.lookup(path)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func (kv KV) Has(path string) bool {
+	_, err := kv.lookup(path)
+	return err == nil
+}
+
+// Sub returns the map at path, nil if missing or not a map
+func (kv KV) Sub(path string) KV {
+	value, err := kv.lookup(path)
+	if err != nil {
+		return nil
+	}
+	if m, ok := asMap(value); ok {
+		return m
+	}
+
+	return nil
+}
+
+// Set sets value at path, creating the missing maps on the way.
+// An index may address an existing element or append at the end of a list.
+// An existing top-level key equal to path, e.g. "a.b", is overwritten as Get and Delete match it first.
+func (kv KV) Set(path string, value interface{}) error {
+	if kv == nil {
+		return &KeyError{Path: path, Err: fmt.Errorf("nil KV")}
+	}
+	if _, ok := kv[path]; ok {
+		kv[path] = value
+		return nil
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return &KeyError{Path: path, Err: err}
+	}
+	if segments[0].isIndex {
+		return &KeyError{Path: path, Err: fmt.Errorf("path must start with a key")}
+	}
+	if _, err = setIn(map[string]interface{}(kv), segments, value); err != nil {
+		return &KeyError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+func setIn(cur interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment := segments[0]
+	if !segment.isIndex {
+		m, ok := asMap(cur)
+		if !ok {
+			if cur != nil {
+				return nil, fmt.Errorf("cannot set key %q into %T", segment.key, cur)
+			}
+			m = map[string]interface{}{}
+		}
+		child, err := setIn(m[segment.key], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		m[segment.key] = child
+		return m, nil
+	}
+
+	s, ok := cur.([]interface{})
+	if !ok && cur != nil {
+		return nil, fmt.Errorf("cannot set index [%d] into %T", segment.index, cur)
+	}
+	if segment.index > len(s) {
+		return nil, fmt.Errorf("index [%d] out of range", segment.index)
+	}
+	if segment.index == len(s) {
+		s = append(s, nil)
+	}
+	child, err := setIn(s[segment.index], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	s[segment.index] = child
+
+	return s, nil
+}
+
+// Delete removes the value at path and reports whether it existed
+func (kv KV) Delete(path string) bool {
+	if _, ok := kv[path]; ok {
+		delete(kv, path)
+		return true
+	}
+	segments, err := parsePath(path)
+	if err != nil || segments[0].isIndex {
+		return false
+	}
+	_, deleted := deleteIn(map[string]interface{}(kv), segments)
+
+	return deleted
+}
+
+func deleteIn(cur interface{}, segments []pathSegment) (interface{}, bool) {
+	segment := segments[0]
+	if !segment.isIndex {
+		m, ok := asMap(cur)
+		if !ok {
+			return cur, false
+		}
+		child, exists := m[segment.key]
+		if !exists {
+			return cur, false
+		}
+		if len(segments) == 1 {
+			delete(m, segment.key)
+			return m, true
+		}
+		child, deleted := deleteIn(child, segments[1:])
+		m[segment.key] = child
+		return m, deleted
+	}
+
+	s, ok := cur.([]interface{})
+	if !ok || segment.index >= len(s) {
+		return cur, false
+	}
+	if len(segments) == 1 {
+		return append(s[:segment.index], s[segment.index+1:]...), true
+	}
+	child, deleted := deleteIn(s[segment.index], segments[1:])
+	s[segment.index] = child
+
+	return s, deleted
+}
+
+func (kv KV) castE(path string, to func(interface{}) error) error {
+	value, err := kv.lookup(path)
+	if err != nil {
+		return err
+	}
+	if err = to(value); err != nil {
+		return &KeyError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+func (kv KV) GetString(key string) string {
+	return cast.ToString(kv.get(key))
+}
+
+func (kv KV) GetStringE(path string) (string, error) {
+	var out string
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToStringE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetStringOrDefault(path string, defaultValue string) string {
+	if out, err := kv.GetStringE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetBool(key string) bool {
+	return cast.ToBool(kv.get(key))
+}
+
+func (kv KV) GetBoolE(path string) (bool, error) {
+	var out bool
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToBoolE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetBoolOrDefault(path string, defaultValue bool) bool {
+	if out, err := kv.GetBoolE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetDuration(key string) time.Duration {
+	return cast.ToDuration(kv.get(key))
+}
+
+func (kv KV) GetDurationE(path string) (time.Duration, error) {
+	var out time.Duration
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToDurationE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetDurationOrDefault(path string, defaultValue time.Duration) time.Duration {
+	if out, err := kv.GetDurationE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetFloat64(key string) float64 {
+	return cast.ToFloat64(kv.get(key))
+}
+
+func (kv KV) GetFloat64E(path string) (float64, error) {
+	var out float64
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToFloat64E(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetFloat64OrDefault(path string, defaultValue float64) float64 {
+	if out, err := kv.GetFloat64E(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetInt(key string) int {
+	return cast.ToInt(kv.get(key))
+}
+
+func (kv KV) GetIntE(path string) (int, error) {
+	var out int
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToIntE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetIntOrDefault(path string, defaultValue int) int {
+	if out, err := kv.GetIntE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetInt32(key string) int32 {
+	return cast.ToInt32(kv.get(key))
+}
+
+func (kv KV) GetInt32E(path string) (int32, error) {
+	var out int32
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToInt32E(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetInt32OrDefault(path string, defaultValue int32) int32 {
+	if out, err := kv.GetInt32E(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetInt64(key string) int64 {
+	return cast.ToInt64(kv.get(key))
+}
+
+func (kv KV) GetInt64E(path string) (int64, error) {
+	var out int64
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToInt64E(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetInt64OrDefault(path string, defaultValue int64) int64 {
+	if out, err := kv.GetInt64E(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetUint(key string) uint {
+	return cast.ToUint(kv.get(key))
+}
+
+func (kv KV) GetUintE(path string) (uint, error) {
+	var out uint
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToUintE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetUintOrDefault(path string, defaultValue uint) uint {
+	if out, err := kv.GetUintE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetUint32(key string) uint32 {
+	return cast.ToUint32(kv.get(key))
+}
+
+func (kv KV) GetUint32E(path string) (uint32, error) {
+	var out uint32
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToUint32E(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetUint32OrDefault(path string, defaultValue uint32) uint32 {
+	if out, err := kv.GetUint32E(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetUint64(key string) uint64 {
+	return cast.ToUint64(kv.get(key))
+}
+
+func (kv KV) GetUint64E(path string) (uint64, error) {
+	var out uint64
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToUint64E(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetUint64OrDefault(path string, defaultValue uint64) uint64 {
+	if out, err := kv.GetUint64E(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetStringMap(key string) map[string]interface{} {
+	return cast.ToStringMap(kv.get(key))
+}
+
+func (kv KV) GetStringMapE(path string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToStringMapE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetStringMapOrDefault(path string, defaultValue map[string]interface{}) map[string]interface{} {
+	if out, err := kv.GetStringMapE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetStringMapString(key string) map[string]string {
+	return cast.ToStringMapString(kv.get(key))
+}
+
+func (kv KV) GetStringMapStringE(path string) (map[string]string, error) {
+	var out map[string]string
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToStringMapStringE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetStringMapStringOrDefault(path string, defaultValue map[string]string) map[string]string {
+	if out, err := kv.GetStringMapStringE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetStringMapStringSlice(key string) map[string][]string {
+	return cast.ToStringMapStringSlice(kv.get(key))
+}
+
+func (kv KV) GetStringMapStringSliceE(path string) (map[string][]string, error) {
+	var out map[string][]string
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToStringMapStringSliceE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetStringMapStringSliceOrDefault(path string, defaultValue map[string][]string) map[string][]string {
+	if out, err := kv.GetStringMapStringSliceE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetStringSlice(key string) []string {
+	return cast.ToStringSlice(kv.get(key))
+}
+
+func (kv KV) GetStringSliceE(path string) ([]string, error) {
+	var out []string
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToStringSliceE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetStringSliceOrDefault(path string, defaultValue []string) []string {
+	if out, err := kv.GetStringSliceE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
+
+func (kv KV) GetTime(key string) time.Time {
+	return cast.ToTime(kv.get(key))
+}
+
+func (kv KV) GetTimeE(path string) (time.Time, error) {
+	var out time.Time
+	err := kv.castE(path, func(value interface{}) (err error) {
+		out, err = cast.ToTimeE(value)
+		return err
+	})
+
+	return out, err
+}
+
+func (kv KV) GetTimeOrDefault(path string, defaultValue time.Time) time.Time {
+	if out, err := kv.GetTimeE(path); err == nil {
+		return out
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,105 @@
+package aconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestKV() KV {
+	return KV{
+		"a.b": "dotted",
+		"a": map[string]interface{}{
+			"b": "nested",
+			"list": []interface{}{
+				map[string]interface{}{"c": 1},
+				"x",
+			},
+		},
+		"n": nil,
+	}
+}
+
+func TestKVGetE(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{"a.b", "dotted", false},
+		{"a.list[0].c", 1, false},
+		{"a.list[1]", "x", false},
+		{"a.list[2]", nil, true},
+		{"a.list[0].d", nil, true},
+		{"a.b.c", nil, true},
+		{"n", nil, false},
+		{"missing", nil, true},
+		{"a.list[", nil, true},
+		{"a.list[x]", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := newTestKV().GetE(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetE(%q) err = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			var keyErr *KeyError
+			if !errors.As(err, &keyErr) || keyErr.Path != tt.path {
+				t.Errorf("GetE(%q) err = %#v, want KeyError of the path", tt.path, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetE(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestKVSetDelete(t *testing.T) {
+	kv := KV{}
+	for _, set := range []struct {
+		path  string
+		value interface{}
+	}{
+		{"a.b", 1},
+		{"a.list[0]", "x"},
+		{"a.list[1]", "y"},
+		{"c[0].d", true},
+	} {
+		if err := kv.Set(set.path, set.value); err != nil {
+			t.Fatalf("Set(%q) err = %v", set.path, err)
+		}
+	}
+	if got := kv.GetInt("a.b"); got != 1 {
+		t.Errorf("a.b = %v", got)
+	}
+	if got := kv.GetString("a.list[1]"); got != "y" {
+		t.Errorf("a.list[1] = %v", got)
+	}
+	if !kv.Delete("a.list[0]") || kv.GetString("a.list[0]") != "y" {
+		t.Errorf("Delete(a.list[0]) left %v", kv.Get("a.list"))
+	}
+	if err := kv.Set("a.list[5]", "z"); err == nil {
+		t.Errorf("Set(a.list[5]) succeeded past the end of the list")
+	}
+	if kv.Delete("a.missing") {
+		t.Errorf("Delete(a.missing) = true")
+	}
+}
+
+func TestKVSetLiteralKey(t *testing.T) {
+	kv := KV{"a.b": 1}
+	if err := kv.Set("a.b", 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := kv.Get("a.b"); got != 2 {
+		t.Errorf("a.b = %v, want 2", got)
+	}
+	if _, ok := kv["a"]; ok {
+		t.Errorf("Set created the nested a: %v", kv)
+	}
+	if !kv.Delete("a.b") || len(kv) != 0 {
+		t.Errorf("Delete(a.b) left %v", kv)
+	}
+}
@@ -0,0 +1,274 @@
+package aconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Loader loads an Application from an ordered list of manifest files.
+// Each file is deep-merged onto the previous ones: maps are merged, other values
+// are overridden and an explicit null deletes the key.
+type Loader struct {
+	Files []string
+	// Format of the files, detected by extension or content if empty
+	Format          Format
+	PreProcessFuncs []PreProcessFunc
+	// PostProcessFuncs run on the Application after the environment overlay, e.g. Decrypter
+	PostProcessFuncs []PostProcessFunc
+	// EnvPrefix is the prefix of the environment overlay, DefaultEnvPrefix if empty
+	EnvPrefix         string
+	DisableEnvOverlay bool
+	// SecretResolver resolves the secret references, DefaultSecretResolver if nil.
+	// Its cache is purged before resolving, so that every Load, e.g. a Watcher reload, reads the rotated secrets.
+	SecretResolver         *SecretResolver
+	DisableSecretResolving bool
+	// Strict rejects unknown fields and invalid manifests, see Application.Validate
+	Strict bool
+}
+
+// NewLayered loads and merges configFiles in order
+func NewLayered(configFiles []string, funcs ...PreProcessFunc) (*Application, error) {
+	loader := &Loader{
+		Files:           configFiles,
+		PreProcessFuncs: funcs,
+	}
+
+	return loader.Load()
+}
+
+// NewWithProfiles loads configFile merged with its profile files, see ProfileFiles
+func NewWithProfiles(configFile string, profiles []string, funcs ...PreProcessFunc) (*Application, error) {
+	return NewLayered(ProfileFiles(configFile, profiles...), funcs...)
+}
+
+// ProfileFiles returns configFile followed by a file per profile,
+// e.g. app.yaml with profile prod gives app.yaml and app.prod.yaml
+func ProfileFiles(configFile string, profiles ...string) []string {
+	files := []string{configFile}
+	ext := filepath.Ext(configFile)
+	base := strings.TrimSuffix(configFile, ext)
+	for _, profile := range profiles {
+		if profile == "" {
+			continue
+		}
+		files = append(files, base+"."+profile+ext)
+	}
+
+	return files
+}
+
+type layer struct {
+	name string
+	data []byte
+}
+
+func (l *Loader) Load() (*Application, error) {
+	if len(l.Files) == 0 {
+		return nil, fmt.Errorf("aconfig: no config file")
+	}
+
+	layers := make([]layer, 0, len(l.Files))
+	for _, file := range l.Files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer{name: file, data: data})
+	}
+
+	return l.loadLayers(layers)
+}
+
+// LoadData loads data instead of the files, name is reported as its source
+func (l *Loader) LoadData(name string, data []byte) (*Application, error) {
+	return l.loadLayers([]layer{{name: name, data: data}})
+}
+
+func (l *Loader) loadLayers(layers []layer) (*Application, error) {
+	merged := map[string]interface{}{}
+	sources := map[string]string{}
+	for _, ly := range layers {
+		data, err := l.preProcess(ly.data)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := unmarshalTree(data, l.format(ly.name, data))
+		if err != nil {
+			return nil, fmt.Errorf("aconfig: %s: %v", ly.name, err)
+		}
+		mergeTree(merged, tree, "", ly.name, sources)
+	}
+
+	application, errs, err := l.build(merged, sources, l.envPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Strict {
+		if verrs, ok := application.Validate().(ValidationErrors); ok {
+			errs = append(errs, verrs...)
+		}
+		if len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	return application, nil
+}
+
+func (l *Loader) envPrefix() string {
+	if l.EnvPrefix == "" {
+		return DefaultEnvPrefix
+	}
+
+	return l.EnvPrefix
+}
+
+// build decodes the merged tree and runs the overlays, in strict mode the unknown fields are returned
+func (l *Loader) build(merged map[string]interface{}, sources map[string]string, envPrefix string) (*Application, ValidationErrors, error) {
+	application, decoded, err := convert(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs ValidationErrors
+	if l.Strict {
+		unknownFields(&errs, merged, reflect.TypeOf(decoded), "", sources)
+	}
+	application.sources = sources
+
+	if !l.DisableEnvOverlay {
+		if _, err = application.OverlayEnv(envPrefix); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !l.DisableSecretResolving {
+		resolver := l.SecretResolver
+		if resolver == nil {
+			resolver = DefaultSecretResolver
+		}
+		resolver.Purge()
+		if err = application.ResolveSecrets(resolver); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, f := range l.PostProcessFuncs {
+		if err = f(application); err != nil {
+			return nil, nil, err
+		}
+	}
+	application.Default()
+
+	return application, errs, nil
+}
+
+func (l *Loader) preProcess(data []byte) ([]byte, error) {
+	var err error
+	// Run the funcs on it
+	for _, f := range l.PreProcessFuncs {
+		if data, err = f(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+func (l *Loader) format(name string, data []byte) Format {
+	if l.Format != "" {
+		return l.Format
+	}
+
+	return DetectFormat(name, data)
+}
+
+// mergeTree merges src into dst and records the source of every leaf it sets
+func mergeTree(dst, src map[string]interface{}, prefix, source string, sources map[string]string) {
+	for k, sv := range src {
+		path := joinKey(prefix, k)
+		if sv == nil {
+			delete(dst, k)
+			deleteSources(sources, path)
+			continue
+		}
+
+		sm, srcIsMap := sv.(map[string]interface{})
+		dm, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeTree(dm, sm, path, source, sources)
+			continue
+		}
+
+		deleteSources(sources, path)
+		if srcIsMap {
+			dm = map[string]interface{}{}
+			mergeTree(dm, sm, path, source, sources)
+			if len(sm) == 0 {
+				sources[path] = source
+			}
+			dst[k] = dm
+			continue
+		}
+		dst[k] = sv
+		sources[path] = source
+	}
+}
+
+func deleteSources(sources map[string]string, path string) {
+	for k := range sources {
+		if k == path || strings.HasPrefix(k, path+".") {
+			delete(sources, k)
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// Sources returns the file or environment variable each value of the Application comes from,
+// keyed by dotted path, e.g. spec.secondary_ports.mysql.options.password
+func (a *Application) Sources() map[string]string {
+	return a.sources
+}
+
+// Source returns where the value at path comes from, looking up its closest recorded parent
+func (a *Application) Source(path string) string {
+	for {
+		if source, ok := a.sources[path]; ok {
+			return source
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return ""
+		}
+		path = path[:i]
+	}
+}
+
+// SourcePaths returns the recorded paths in order
+func (a *Application) SourcePaths() []string {
+	paths := make([]string, 0, len(a.sources))
+	for path := range a.sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+func (a *Application) setSource(path, source string) {
+	if a.sources == nil {
+		a.sources = map[string]string{}
+	}
+	deleteSources(a.sources, path)
+	a.sources[path] = source
+}
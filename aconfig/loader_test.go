@@ -0,0 +1,65 @@
+package aconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeTree(t *testing.T) {
+	tests := []struct {
+		name        string
+		dst, src    map[string]interface{}
+		want        map[string]interface{}
+		wantSources map[string]string
+	}{
+		{
+			name:        "maps merge",
+			dst:         map[string]interface{}{"a": map[string]interface{}{"x": 1, "y": 2}},
+			src:         map[string]interface{}{"a": map[string]interface{}{"y": 3}},
+			want:        map[string]interface{}{"a": map[string]interface{}{"x": 1, "y": 3}},
+			wantSources: map[string]string{"a.x": "base", "a.y": "over"},
+		},
+		{
+			name:        "scalar overrides map",
+			dst:         map[string]interface{}{"a": map[string]interface{}{"x": 1}},
+			src:         map[string]interface{}{"a": "s"},
+			want:        map[string]interface{}{"a": "s"},
+			wantSources: map[string]string{"a": "over"},
+		},
+		{
+			name:        "lists are replaced",
+			dst:         map[string]interface{}{"l": []interface{}{1, 2}},
+			src:         map[string]interface{}{"l": []interface{}{3}},
+			want:        map[string]interface{}{"l": []interface{}{3}},
+			wantSources: map[string]string{"l": "over"},
+		},
+		{
+			name:        "null deletes",
+			dst:         map[string]interface{}{"a": map[string]interface{}{"x": 1}, "b": 2},
+			src:         map[string]interface{}{"a": nil},
+			want:        map[string]interface{}{"b": 2},
+			wantSources: map[string]string{"b": "base"},
+		},
+		{
+			name:        "empty map",
+			dst:         map[string]interface{}{},
+			src:         map[string]interface{}{"a": map[string]interface{}{}},
+			want:        map[string]interface{}{"a": map[string]interface{}{}},
+			wantSources: map[string]string{"a": "over"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := map[string]interface{}{}
+			sources := map[string]string{}
+			mergeTree(merged, tt.dst, "", "base", sources)
+			mergeTree(merged, tt.src, "", "over", sources)
+			if !reflect.DeepEqual(merged, tt.want) {
+				t.Errorf("merged = %v, want %v", merged, tt.want)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Errorf("sources = %v, want %v", sources, tt.wantSources)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+const RedactedValue = "******"
+
+// DefaultRedactPatterns match the dotted paths of the values hidden by a Redactor
+var DefaultRedactPatterns = []string{
+	`password`,
+	`passwd`,
+	`secret`,
+	`token`,
+	`credential`,
+	`private_key`,
+	`(^|\.)encryptor\.s$`,
+}
+
+// Redactor hides the values whose dotted path matches one of its case-insensitive patterns
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles patterns, DefaultRedactPatterns if none
+func NewRedactor(patterns ...string) (*Redactor, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactPatterns
+	}
+
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("aconfig: redact pattern %q: %v", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+func (r *Redactor) Match(path string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactValue converts v into its json tree, whose values at paths matching the patterns,
+// or reported by isSecret, are replaced by RedactedValue. prefix is prepended to the paths.
+func (r *Redactor) RedactValue(v interface{}, prefix string, isSecret func(path string) bool) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err = json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	return r.redact(tree, prefix, isSecret), nil
+}
+
+// RedactApplication returns the json tree of a with the patterns and the secrets of a redacted
+func (r *Redactor) RedactApplication(a *Application) (interface{}, error) {
+	return r.RedactValue(a, "", a.IsSecret)
+}
+
+func (r *Redactor) redact(v interface{}, path string, isSecret func(path string) bool) interface{} {
+	if path != "" && (r.Match(path) || (isSecret != nil && isSecret(path))) {
+		return RedactedValue
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range value {
+			value[k] = r.redact(elem, joinKey(path, k), isSecret)
+		}
+	case []interface{}:
+		for i, elem := range value {
+			value[i] = r.redact(elem, fmt.Sprintf("%s[%d]", path, i), isSecret)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,179 @@
+package aconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SecretSchemeFile     = "file"
+	SecretSchemeEnv      = "env"
+	SecretSchemeProvider = "provider"
+)
+
+// secretReferenceRegexp matches ${file:/run/secrets/db_pw}, ${env:DB_PW} and ${provider:name/key}
+var secretReferenceRegexp = regexp.MustCompile(`\$\{(file|env|provider):([^}]+)\}`)
+
+// SecretProvider resolves the key of a ${provider:name/key} reference
+type SecretProvider interface {
+	Resolve(key string) (string, error)
+}
+
+type SecretProviderFunc func(key string) (string, error)
+
+func (f SecretProviderFunc) Resolve(key string) (string, error) {
+	return f(key)
+}
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// SecretResolver resolves the secret references with the builtin file and env schemes
+// and the registered providers, and caches the resolved values
+type SecretResolver struct {
+	// TTL of the cached values, they never expire if zero
+	TTL time.Duration
+
+	mu        sync.RWMutex
+	providers map[string]SecretProvider
+	cache     map[string]cachedSecret
+}
+
+// DefaultSecretTTL is the TTL of the values cached by the DefaultSecretResolver
+const DefaultSecretTTL = time.Minute
+
+var DefaultSecretResolver = &SecretResolver{
+	TTL:       DefaultSecretTTL,
+	providers: map[string]SecretProvider{},
+	cache:     map[string]cachedSecret{},
+}
+
+func NewSecretResolver() *SecretResolver {
+	return &SecretResolver{
+		providers: map[string]SecretProvider{},
+		cache:     map[string]cachedSecret{},
+	}
+}
+
+// RegisterSecretProvider registers provider by name on the DefaultSecretResolver
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	DefaultSecretResolver.Register(name, provider)
+}
+
+func (r *SecretResolver) Register(name string, provider SecretProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[name] = provider
+}
+
+// Purge drops the cached values
+func (r *SecretResolver) Purge() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cache = map[string]cachedSecret{}
+}
+
+// Resolve resolves a single reference such as ${env:DB_PW}
+func (r *SecretResolver) Resolve(reference string) (string, error) {
+	m := secretReferenceRegexp.FindStringSubmatch(reference)
+	if m == nil || m[0] != reference {
+		return "", fmt.Errorf("invalid secret reference %q", reference)
+	}
+
+	r.mu.RLock()
+	cached, ok := r.cache[reference]
+	r.mu.RUnlock()
+	if ok && (cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt)) {
+		return cached.value, nil
+	}
+
+	value, err := r.resolve(m[1], m[2])
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", reference, err)
+	}
+
+	cached = cachedSecret{value: value}
+	if r.TTL > 0 {
+		cached.expiresAt = time.Now().Add(r.TTL)
+	}
+	r.mu.Lock()
+	r.cache[reference] = cached
+	r.mu.Unlock()
+
+	return value, nil
+}
+
+func (r *SecretResolver) resolve(scheme, key string) (string, error) {
+	switch scheme {
+	case SecretSchemeFile:
+		data, err := ioutil.ReadFile(key)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case SecretSchemeEnv:
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s not set", key)
+		}
+		return value, nil
+	}
+
+	i := strings.Index(key, "/")
+	if i <= 0 {
+		return "", fmt.Errorf("provider reference must be name/key")
+	}
+	name := key[:i]
+
+	r.mu.RLock()
+	provider, ok := r.providers[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("secret provider %q not registered", name)
+	}
+
+	return provider.Resolve(key[i+1:])
+}
+
+// SecretResolving returns a PostProcessFunc resolving the secret references with r
+func SecretResolving(r *SecretResolver) PostProcessFunc {
+	return func(a *Application) error {
+		return a.ResolveSecrets(r)
+	}
+}
+
+// ResolveSecrets replaces the secret references in the secondary port options and the custom config
+// by their values, the resolved paths are reported as secrets
+func (a *Application) ResolveSecrets(r *SecretResolver) error {
+	return a.resolveValues(func(path, s string) (string, bool, error) {
+		if !strings.Contains(s, "${") {
+			return s, false, nil
+		}
+
+		var resolveErr error
+		resolved := secretReferenceRegexp.ReplaceAllStringFunc(s, func(reference string) string {
+			if resolveErr != nil {
+				return ""
+			}
+			value, err := r.Resolve(reference)
+			if err != nil {
+				resolveErr = err
+			}
+			return value
+		})
+		if resolveErr != nil {
+			return "", false, fmt.Errorf("aconfig: unresolved %s: %v", path, resolveErr)
+		}
+
+		return resolved, resolved != s, nil
+	})
+}
@@ -0,0 +1,32 @@
+package aconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadReadsRotatedSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secretFile := filepath.Join(dir, "password")
+	manifest := []byte("spec:\n  custom_config:\n    password: ${file:" + secretFile + "}\n")
+	loader := &Loader{DisableEnvOverlay: true}
+	for _, password := range []string{"old", "new"} {
+		if err = ioutil.WriteFile(secretFile, []byte(password+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		a, err := loader.LoadData("app.yaml", manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.GetCustomConfig().GetString("password"); got != password {
+			t.Errorf("password = %q, want %q", got, password)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package aconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+const DefaultNamespace = "default"
+
+// documentSeparatorRegexp matches the --- lines separating YAML documents
+var documentSeparatorRegexp = regexp.MustCompile(`(?m)^---[ \t]*(#.*)?$`)
+
+// ApplicationSet holds the Applications of multi-document manifests indexed by namespace/name
+type ApplicationSet struct {
+	applications map[string]*Application
+	keys         []string
+}
+
+// NewSet loads the "---" separated Applications of configFile, see Loader.LoadSet
+func NewSet(configFile string, funcs ...PreProcessFunc) (*ApplicationSet, error) {
+	loader := &Loader{
+		Files:           []string{configFile},
+		PreProcessFuncs: funcs,
+	}
+
+	return loader.LoadSet()
+}
+
+// LoadSet loads every document of the files into an ApplicationSet, the documents with the same
+// namespace/name are merged in order. Every document requires a metadata.name unless the set has only one. The environment overlay of an Application takes
+// the prefix EnvPrefix + NAME + "__", e.g. ALPHA_USER_API__SPEC__CUSTOM_CONFIG__DEBUG.
+// The matched primary ports without location are linked to the Applications of the set.
+func (l *Loader) LoadSet() (*ApplicationSet, error) {
+	if len(l.Files) == 0 {
+		return nil, fmt.Errorf("aconfig: no config file")
+	}
+
+	type document struct {
+		source string
+		tree   map[string]interface{}
+	}
+	var documents []document
+	for _, file := range l.Files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = l.preProcess(data); err != nil {
+			return nil, err
+		}
+
+		format := l.format(file, data)
+		parts := [][]byte{data}
+		if format == FormatYAML {
+			parts = splitDocuments(data)
+		}
+		for i, part := range parts {
+			tree, err := unmarshalTree(part, format)
+			if err != nil {
+				return nil, fmt.Errorf("aconfig: %s: document %d: %v", file, i, err)
+			}
+			if len(tree) == 0 {
+				continue
+			}
+			source := file
+			if len(parts) > 1 {
+				source = fmt.Sprintf("%s#%d", file, i)
+			}
+			documents = append(documents, document{source: source, tree: tree})
+		}
+	}
+
+	trees := map[string]map[string]interface{}{}
+	sources := map[string]map[string]string{}
+	var keys []string
+	for _, d := range documents {
+		// The unnamed documents of a set would silently merge into one Application
+		if len(documents) > 1 && treeName(d.tree) == "" {
+			return nil, fmt.Errorf("aconfig: %s: metadata.name is required in a multi-document set", d.source)
+		}
+		key := treeKey(d.tree)
+		if _, exists := trees[key]; !exists {
+			trees[key] = map[string]interface{}{}
+			sources[key] = map[string]string{}
+			keys = append(keys, key)
+		}
+		mergeTree(trees[key], d.tree, "", d.source, sources[key])
+	}
+
+	set := &ApplicationSet{applications: map[string]*Application{}}
+	var errs ValidationErrors
+	for _, key := range keys {
+		application, unknown, err := l.build(trees[key], sources[key], l.envPrefix()+envName(key)+envPathSeparator)
+		if err != nil {
+			return nil, fmt.Errorf("aconfig: %s: %v", key, err)
+		}
+		errs = append(errs, prefixErrors(key, unknown)...)
+		set.applications[key] = application
+		set.keys = append(set.keys, key)
+	}
+	set.Link()
+
+	if l.Strict {
+		for _, key := range set.keys {
+			if verrs, ok := set.applications[key].Validate().(ValidationErrors); ok {
+				errs = append(errs, prefixErrors(key, verrs)...)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	return set, nil
+}
+
+func splitDocuments(data []byte) [][]byte {
+	var documents [][]byte
+	for _, document := range documentSeparatorRegexp.Split(string(data), -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		documents = append(documents, []byte(document))
+	}
+
+	return documents
+}
+
+func treeKey(tree map[string]interface{}) string {
+	var namespace string
+	if metadata, ok := tree["metadata"].(map[string]interface{}); ok {
+		namespace, _ = metadata["namespace"].(string)
+	}
+
+	return setKey(namespace, treeName(tree))
+}
+
+func treeName(tree map[string]interface{}) string {
+	metadata, _ := tree["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+
+	return name
+}
+
+func setKey(namespace, name string) string {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	return namespace + "/" + name
+}
+
+// envName turns namespace/name into the NAME of its environment prefix, the default namespace is omitted
+func envName(key string) string {
+	key = strings.TrimPrefix(key, DefaultNamespace+"/")
+	replacer := strings.NewReplacer("/", "_", "-", "_", ".", "_")
+
+	return strings.ToUpper(replacer.Replace(key))
+}
+
+func prefixErrors(key string, errs ValidationErrors) ValidationErrors {
+	prefixed := make(ValidationErrors, 0, len(errs))
+	for _, e := range errs {
+		prefixed = append(prefixed, &FieldError{Path: key + ":" + e.Path, Message: e.Message})
+	}
+
+	return prefixed
+}
+
+// Get returns the Application name of namespace, the default namespace if empty
+func (s *ApplicationSet) Get(namespace, name string) *Application {
+	return s.applications[setKey(namespace, name)]
+}
+
+// Lookup resolves an application name as referenced from namespace:
+// "namespace/name", or "name" in namespace, or "name" unique across namespaces
+func (s *ApplicationSet) Lookup(reference, namespace string) *Application {
+	if strings.Contains(reference, "/") {
+		return s.applications[reference]
+	}
+	if application, ok := s.applications[setKey(namespace, reference)]; ok {
+		return application
+	}
+
+	var found *Application
+	for _, key := range s.keys {
+		if strings.HasSuffix(key, "/"+reference) {
+			if found != nil {
+				return nil
+			}
+			found = s.applications[key]
+		}
+	}
+
+	return found
+}
+
+// Applications returns the Applications in the order of the manifests
+func (s *ApplicationSet) Applications() []*Application {
+	applications := make([]*Application, 0, len(s.keys))
+	for _, key := range s.keys {
+		applications = append(applications, s.applications[key])
+	}
+
+	return applications
+}
+
+// Keys returns the namespace/name of the Applications in the order of the manifests
+func (s *ApplicationSet) Keys() []string {
+	return s.keys
+}
+
+// Link fills the matched primary ports without location from the Application they name:
+// its primary port with the same port name, or its only primary port with the same interface name
+func (s *ApplicationSet) Link() {
+	for _, key := range s.keys {
+		application := s.applications[key]
+		for _, name := range application.secondaryPortNames() {
+			sp := application.Spec.SecondaryPorts[name]
+			matched := sp.MatchedPrimaryPort
+			if matched == nil || matched.Location != nil || matched.ApplicationName == "" {
+				continue
+			}
+			target := s.Lookup(matched.ApplicationName, application.Namespace)
+			if target == nil {
+				continue
+			}
+			ppName, pp, ok := target.matchPrimaryPort(name, sp.Interface.Name)
+			if !ok || pp.Location == nil {
+				continue
+			}
+
+			location := *pp.Location
+			matched.Location = &location
+			application.setSource("spec.secondary_ports."+string(name)+".matched_primary_port.location",
+				fmt.Sprintf("link:%s/spec.primary_ports.%s", setKey(target.Namespace, target.Name), ppName))
+		}
+		application.Default()
+	}
+}
+
+func (a *Application) matchPrimaryPort(name PortName, interfaceName string) (PortName, PrimaryPort, bool) {
+	if pp, ok := a.Spec.PrimaryPorts[name]; ok {
+		return name, pp, true
+	}
+
+	var names []string
+	for ppName, pp := range a.Spec.PrimaryPorts {
+		if interfaceName != "" && pp.Interface.Name == interfaceName {
+			names = append(names, string(ppName))
+		}
+	}
+	if len(names) != 1 {
+		return "", PrimaryPort{}, false
+	}
+
+	return PortName(names[0]), a.Spec.PrimaryPorts[PortName(names[0])], true
+}
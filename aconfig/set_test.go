@@ -0,0 +1,195 @@
+package aconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const setManifest = `kind: Application
+api_version: v1
+metadata:
+  name: users
+spec:
+  primary_ports:
+    http:
+      interface:
+        name: http
+      location:
+        address: users.local
+        port: 8080
+---
+kind: Application
+api_version: v1
+metadata:
+  name: orders
+  namespace: shop
+spec:
+  secondary_ports:
+    users:
+      interface:
+        name: http
+      matched_primary_port:
+        application_name: users
+---
+# a later document of users overrides its port
+metadata:
+  name: users
+spec:
+  primary_ports:
+    http:
+      location:
+        port: 8081
+`
+
+func writeSetFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "aconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	var files []string
+	for i, content := range contents {
+		file := filepath.Join(dir, "set"+string(rune('0'+i))+".yaml")
+		if err = ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	return files
+}
+
+func TestLoadSet(t *testing.T) {
+	loader := &Loader{Files: writeSetFiles(t, setManifest), Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+	set, err := loader.LoadSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(set.Keys(), ","); got != "default/users,shop/orders" {
+		t.Errorf("Keys() = %s", got)
+	}
+	users := set.Get("", "users")
+	if port := users.Spec.PrimaryPorts["http"].Location.Port; port != 8081 {
+		t.Errorf("users port = %d, want the merged 8081", port)
+	}
+	orders := set.Get("shop", "orders")
+	location := orders.GetMatchedPrimaryPortLocation("users")
+	if location == nil || location.Address != "users.local" || location.Port != 8081 {
+		t.Fatalf("linked location = %+v", location)
+	}
+	if got := orders.Source("spec.secondary_ports.users.matched_primary_port.location"); got != "link:default/users/spec.primary_ports.http" {
+		t.Errorf("linked source = %q", got)
+	}
+
+	tests := []struct {
+		reference string
+		namespace string
+		want      *Application
+	}{
+		{"users", "shop", users},
+		{"default/users", "", users},
+		{"orders", "", orders},
+		{"shop/orders", "", orders},
+		{"payments", "", nil},
+	}
+	for _, tt := range tests {
+		if got := set.Lookup(tt.reference, tt.namespace); got != tt.want {
+			t.Errorf("Lookup(%q, %q) = %v", tt.reference, tt.namespace, got)
+		}
+	}
+}
+
+func TestLoadSetNames(t *testing.T) {
+	unnamed := "kind: Application\napi_version: v1\n"
+	tests := []struct {
+		name     string
+		contents []string
+		wantErr  string
+		wantKeys string
+	}{
+		{name: "single unnamed document", contents: []string{unnamed}, wantKeys: "default/"},
+		{name: "unnamed document of a set", contents: []string{setManifest + "---\n" + unnamed}, wantErr: "#3: metadata.name is required"},
+		{name: "unnamed documents", contents: []string{unnamed + "---\n" + unnamed}, wantErr: "#0: metadata.name is required"},
+		{name: "unnamed file of a set", contents: []string{setManifest, unnamed}, wantErr: "set1.yaml: metadata.name is required"},
+		{name: "named files", contents: []string{setManifest, "metadata:\n  name: payments\n"}, wantKeys: "default/users,shop/orders,default/payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := &Loader{Files: writeSetFiles(t, tt.contents...), DisableEnvOverlay: true, DisableSecretResolving: true}
+			set, err := loader.LoadSet()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadSet() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(set.Keys(), ","); got != tt.wantKeys {
+				t.Errorf("Keys() = %s, want %s", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestLoadSetStrictErrors(t *testing.T) {
+	manifest := setManifest + `---
+kind: Application
+api_version: v1
+metadata:
+  name: payments
+spec:
+  secondary_ports:
+    mysql:
+      interface:
+        name: mysql
+      options:
+        user: root
+      unknown: true
+`
+	loader := &Loader{Files: writeSetFiles(t, manifest), Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+	_, err := loader.LoadSet()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("LoadSet() error = %v, want ValidationErrors", err)
+	}
+
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Path)
+	}
+	want := []string{
+		"default/payments:spec.secondary_ports.mysql.unknown",
+		"default/payments:spec.secondary_ports.mysql.matched_primary_port.location",
+		"default/payments:spec.secondary_ports.mysql.options.database",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestLoadSetEnvOverlay(t *testing.T) {
+	os.Setenv("ALPHA_SHOP_ORDERS__SPEC__CUSTOM_CONFIG__DEBUG", "true")
+	defer os.Unsetenv("ALPHA_SHOP_ORDERS__SPEC__CUSTOM_CONFIG__DEBUG")
+
+	loader := &Loader{Files: writeSetFiles(t, setManifest), DisableSecretResolving: true}
+	set, err := loader.LoadSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := set.Get("shop", "orders").Spec.CustomConfig.GetString("debug"); got != "true" {
+		t.Errorf("orders debug = %q, want true", got)
+	}
+	if got := set.Get("", "users").Spec.CustomConfig.Get("debug"); got != nil {
+		t.Errorf("users debug = %v, want nil", got)
+	}
+}
@@ -0,0 +1,292 @@
+package aconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	KindApplication = "Application"
+	APIVersionV1    = "v1"
+
+	minPort = 1
+	maxPort = 65535
+)
+
+// FieldError is a validation failure of the value at Path
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors aggregates the FieldErrors of a validation pass
+type ValidationErrors []*FieldError
+
+func (es ValidationErrors) Error() string {
+	messages := make([]string, 0, len(es))
+	for _, e := range es {
+		messages = append(messages, e.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (es *ValidationErrors) add(path, format string, a ...interface{}) {
+	*es = append(*es, &FieldError{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+func (es ValidationErrors) errorOrNil() error {
+	if len(es) == 0 {
+		return nil
+	}
+
+	return es
+}
+
+// PortSchema describes the secondary ports of an interface name, e.g. mysql
+type PortSchema struct {
+	// RequiredOptions must be set in SecondaryPort.Options
+	RequiredOptions []string
+	// DefaultOptions are set in SecondaryPort.Options when missing
+	DefaultOptions KV
+	// RequireLocation requires SecondaryPort.MatchedPrimaryPort.Location, or its ApplicationName
+	// for the ports located through discovery
+	RequireLocation bool
+	// DefaultPort is set on a matched location without port
+	DefaultPort int
+}
+
+var (
+	portSchemasMu sync.RWMutex
+	portSchemas   = map[string]PortSchema{
+		"mysql": {RequiredOptions: []string{"user", "database"}, RequireLocation: true, DefaultPort: 3306},
+		"http":  {RequireLocation: true, DefaultPort: 80},
+		"https": {RequireLocation: true, DefaultPort: 443},
+	}
+)
+
+// RegisterPortSchema registers the schema of the secondary ports whose interface is named interfaceName
+func RegisterPortSchema(interfaceName string, schema PortSchema) {
+	portSchemasMu.Lock()
+	defer portSchemasMu.Unlock()
+
+	portSchemas[interfaceName] = schema
+}
+
+func getPortSchema(interfaceName string) (PortSchema, bool) {
+	portSchemasMu.RLock()
+	defer portSchemasMu.RUnlock()
+
+	schema, ok := portSchemas[interfaceName]
+	return schema, ok
+}
+
+// Default sets the defaults of the port schemas on the secondary ports
+func (a *Application) Default() {
+	for _, name := range a.secondaryPortNames() {
+		sp := a.Spec.SecondaryPorts[name]
+		schema, ok := getPortSchema(sp.Interface.Name)
+		if !ok {
+			continue
+		}
+		path := "spec.secondary_ports." + string(name)
+
+		for key, value := range schema.DefaultOptions {
+			if _, exists := sp.Options[key]; exists {
+				continue
+			}
+			if sp.Options == nil {
+				sp.Options = KV{}
+			}
+			sp.Options[key] = value
+			a.setSource(path+".options."+key, "default")
+		}
+		if schema.DefaultPort != 0 && sp.MatchedPrimaryPort != nil && sp.MatchedPrimaryPort.Location != nil &&
+			sp.MatchedPrimaryPort.Location.Port == 0 {
+			sp.MatchedPrimaryPort.Location.Port = schema.DefaultPort
+			a.setSource(path+".matched_primary_port.location.port", "default")
+		}
+		a.Spec.SecondaryPorts[name] = sp
+	}
+}
+
+// Validate checks the type meta, the locations and the secondary ports against their PortSchema,
+// the returned error is ValidationErrors
+func (a *Application) Validate() error {
+	var errs ValidationErrors
+
+	if a.Kind == "" {
+		errs.add("kind", "required")
+	} else if a.Kind != KindApplication {
+		errs.add("kind", "unsupported kind %q, must be %q", a.Kind, KindApplication)
+	}
+	if a.APIVersion == "" {
+		errs.add("api_version", "required")
+	} else if _, ok := getVersion(KindApplication, a.APIVersion); !ok {
+		errs.add("api_version", "unsupported api version %q, known versions: %s",
+			a.APIVersion, strings.Join(APIVersions(KindApplication), ", "))
+	}
+
+	primaryPortNames := make([]string, 0, len(a.Spec.PrimaryPorts))
+	for name := range a.Spec.PrimaryPorts {
+		primaryPortNames = append(primaryPortNames, string(name))
+	}
+	sort.Strings(primaryPortNames)
+	for _, name := range primaryPortNames {
+		pp := a.Spec.PrimaryPorts[PortName(name)]
+		path := "spec.primary_ports." + name
+		if pp.Interface.Name == "" {
+			errs.add(path+".interface.name", "required")
+		}
+		if pp.Location != nil {
+			validatePort(&errs, path+".location.port", pp.Location.Port, false)
+		}
+	}
+
+	for _, name := range a.secondaryPortNames() {
+		sp := a.Spec.SecondaryPorts[name]
+		path := "spec.secondary_ports." + string(name)
+		if sp.Interface.Name == "" {
+			errs.add(path+".interface.name", "required")
+		}
+
+		var location *Location
+		var applicationName string
+		if sp.MatchedPrimaryPort != nil {
+			location = sp.MatchedPrimaryPort.Location
+			applicationName = sp.MatchedPrimaryPort.ApplicationName
+		}
+		if location != nil {
+			if location.Address == "" {
+				errs.add(path+".matched_primary_port.location.address", "required")
+			}
+			validatePort(&errs, path+".matched_primary_port.location.port", location.Port, true)
+		}
+
+		schema, ok := getPortSchema(sp.Interface.Name)
+		if !ok {
+			continue
+		}
+		if schema.RequireLocation && location == nil && applicationName == "" {
+			errs.add(path+".matched_primary_port.location", "required for interface %q without application_name",
+				sp.Interface.Name)
+		}
+		for _, key := range schema.RequiredOptions {
+			if sp.Options.GetString(key) == "" {
+				errs.add(path+".options."+key, "required for interface %q", sp.Interface.Name)
+			}
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+func validatePort(errs *ValidationErrors, path string, port int, required bool) {
+	if port == 0 && !required {
+		return
+	}
+	if port < minPort || port > maxPort {
+		errs.add(path, "%d out of range [%d, %d]", port, minPort, maxPort)
+	}
+}
+
+func (a *Application) secondaryPortNames() []PortName {
+	names := make([]string, 0, len(a.Spec.SecondaryPorts))
+	for name := range a.Spec.SecondaryPorts {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	portNames := make([]PortName, 0, len(names))
+	for _, name := range names {
+		portNames = append(portNames, PortName(name))
+	}
+
+	return portNames
+}
+
+// ValidateFile loads the layered configFiles in strict mode, for use from CI
+func ValidateFile(configFiles ...string) error {
+	loader := &Loader{
+		Files:  configFiles,
+		Strict: true,
+	}
+	_, err := loader.Load()
+
+	return err
+}
+
+// unknownFields reports the keys of tree which do not match any json field of t
+func unknownFields(errs *ValidationErrors, tree interface{}, t reflect.Type, path string, sources map[string]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := tree.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f, _, ok := fieldByJSONName(reflect.New(t).Elem(), key)
+			if !ok {
+				keyPath := joinKey(path, key)
+				if source := sourceUnder(sources, keyPath); source != "" {
+					errs.add(keyPath, "unknown field (from %s)", source)
+				} else {
+					errs.add(keyPath, "unknown field")
+				}
+				continue
+			}
+			unknownFields(errs, m[key], f.Type(), joinKey(path, key), sources)
+		}
+	case reflect.Map:
+		m, ok := tree.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			unknownFields(errs, m[key], t.Elem(), joinKey(path, key), sources)
+		}
+	case reflect.Slice:
+		s, ok := tree.([]interface{})
+		if !ok {
+			return
+		}
+		for i, value := range s {
+			unknownFields(errs, value, t.Elem(), fmt.Sprintf("%s[%d]", path, i), sources)
+		}
+	}
+}
+
+// sourceUnder returns the source of path or of any value below it
+func sourceUnder(sources map[string]string, path string) string {
+	if source, ok := sources[path]; ok {
+		return source
+	}
+	for k, source := range sources {
+		if strings.HasPrefix(k, path+".") {
+			return source
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,203 @@
+package aconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDiscoveryOnlyPort(t *testing.T) {
+	manifest := strings.Replace(validManifest, `      matched_primary_port:
+        location:
+          address: 127.0.0.1
+`, `      matched_primary_port:
+        application_name: users
+`, 1)
+
+	loader := &Loader{Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+	if _, err := loader.LoadData("app.yaml", []byte(manifest)); err != nil {
+		t.Fatalf("LoadData() error = %v", err)
+	}
+}
+
+func testApplication() *Application {
+	return &Application{
+		TypeMeta:   TypeMeta{Kind: KindApplication, APIVersion: APIVersionV1},
+		ObjectMeta: ObjectMeta{Name: "demo"},
+		Spec: ApplicationSpec{
+			PrimaryPorts: map[PortName]PrimaryPort{
+				"http": {Interface: Interface{Name: "http"}, Location: &Location{Address: "0.0.0.0", Port: 8080}},
+			},
+			SecondaryPorts: map[PortName]SecondaryPort{
+				"mysql": {
+					Interface:          Interface{Name: "mysql"},
+					Options:            KV{"user": "root", "database": "demo"},
+					MatchedPrimaryPort: &MatchedPrimaryPort{Location: &Location{Address: "127.0.0.1", Port: 3306}},
+				},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *Application)
+		want   []string
+	}{
+		{name: "valid", modify: func(a *Application) {}},
+		{name: "missing type meta", modify: func(a *Application) { a.TypeMeta = TypeMeta{} }, want: []string{"kind", "api_version"}},
+		{name: "unsupported kind", modify: func(a *Application) { a.Kind = "Service" }, want: []string{"kind"}},
+		{name: "unknown api version", modify: func(a *Application) { a.APIVersion = "v9" }, want: []string{"api_version"}},
+		{
+			name: "primary port",
+			modify: func(a *Application) {
+				a.Spec.PrimaryPorts["http"] = PrimaryPort{Location: &Location{Port: 70000}}
+			},
+			want: []string{"spec.primary_ports.http.interface.name", "spec.primary_ports.http.location.port"},
+		},
+		{
+			name: "secondary port location",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location = &Location{}
+			},
+			want: []string{
+				"spec.secondary_ports.mysql.matched_primary_port.location.address",
+				"spec.secondary_ports.mysql.matched_primary_port.location.port",
+			},
+		},
+		{
+			name: "required location",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location = nil
+			},
+			want: []string{"spec.secondary_ports.mysql.matched_primary_port.location"},
+		},
+		{
+			name: "application name instead of location",
+			modify: func(a *Application) {
+				*a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort = MatchedPrimaryPort{ApplicationName: "db"}
+			},
+		},
+		{
+			name: "required options",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["mysql"] = SecondaryPort{
+					Interface:          Interface{Name: "mysql"},
+					MatchedPrimaryPort: a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort,
+				}
+			},
+			want: []string{"spec.secondary_ports.mysql.options.user", "spec.secondary_ports.mysql.options.database"},
+		},
+		{
+			name: "port without schema",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["cache"] = SecondaryPort{Interface: Interface{Name: "memcached"}}
+			},
+		},
+		{
+			name: "missing interface",
+			modify: func(a *Application) {
+				a.Spec.SecondaryPorts["cache"] = SecondaryPort{}
+			},
+			want: []string{"spec.secondary_ports.cache.interface.name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := testApplication()
+			tt.modify(a)
+
+			var got []string
+			if err := a.Validate(); err != nil {
+				errs, ok := err.(ValidationErrors)
+				if !ok {
+					t.Fatalf("Validate() error %T is not ValidationErrors", err)
+				}
+				for _, e := range errs {
+					got = append(got, e.Path)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	RegisterPortSchema("test-cache", PortSchema{DefaultOptions: KV{"ttl": 60, "prefix": "app"}, DefaultPort: 11211})
+
+	a := testApplication()
+	a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location.Port = 0
+	a.Spec.SecondaryPorts["cache"] = SecondaryPort{
+		Interface:          Interface{Name: "test-cache"},
+		Options:            KV{"prefix": "custom"},
+		MatchedPrimaryPort: &MatchedPrimaryPort{Location: &Location{Address: "cache", Port: 11212}},
+	}
+	a.Spec.SecondaryPorts["sessions"] = SecondaryPort{
+		Interface:          Interface{Name: "test-cache"},
+		MatchedPrimaryPort: &MatchedPrimaryPort{Location: &Location{Address: "sessions"}},
+	}
+	a.Default()
+
+	tests := []struct {
+		path string
+		got  interface{}
+		want interface{}
+	}{
+		{"mysql port", a.Spec.SecondaryPorts["mysql"].MatchedPrimaryPort.Location.Port, 3306},
+		{"cache port kept", a.Spec.SecondaryPorts["cache"].MatchedPrimaryPort.Location.Port, 11212},
+		{"cache prefix kept", a.Spec.SecondaryPorts["cache"].Options.GetString("prefix"), "custom"},
+		{"cache ttl", a.Spec.SecondaryPorts["cache"].Options.GetInt("ttl"), 60},
+		{"sessions port", a.Spec.SecondaryPorts["sessions"].MatchedPrimaryPort.Location.Port, 11211},
+		{"sessions prefix", a.Spec.SecondaryPorts["sessions"].Options.GetString("prefix"), "app"},
+		{"sessions prefix source", a.Source("spec.secondary_ports.sessions.options.prefix"), "default"},
+		{"mysql port source", a.Source("spec.secondary_ports.mysql.matched_primary_port.location.port"), "default"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.path, tt.got, tt.want)
+		}
+	}
+}
+
+func TestStrictUnknownFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{name: "valid", manifest: validManifest},
+		{name: "top level", manifest: "extra: 1\n" + validManifest, want: []string{"extra"}},
+		{name: "typo in spec", manifest: validManifest + "  custom_confg: {}\n", want: []string{"spec.custom_confg"}},
+		{
+			name:     "nested in a map",
+			manifest: strings.Replace(validManifest, "          address: 127.0.0.1\n", "          address: 127.0.0.1\n          host: db\n", 1),
+			want:     []string{"spec.secondary_ports.mysql.matched_primary_port.location.host"},
+		},
+		{name: "free form custom config", manifest: validManifest + "  custom_config:\n    anything:\n      goes: true\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := &Loader{Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+			_, err := loader.LoadData("app.yaml", []byte(tt.manifest))
+
+			var got []string
+			if err != nil {
+				errs, ok := err.(ValidationErrors)
+				if !ok {
+					t.Fatalf("LoadData() error = %v", err)
+				}
+				for _, e := range errs {
+					got = append(got, e.Path)
+					if !strings.Contains(e.Message, "unknown field (from app.yaml)") {
+						t.Errorf("message = %q, want the source of the field", e.Message)
+					}
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("unknown fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
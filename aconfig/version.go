@@ -0,0 +1,134 @@
+package aconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LatestAPIVersion is the api version of the Application type
+const LatestAPIVersion = APIVersionV1
+
+// VersionConverter loads the manifests of a Kind/APIVersion
+type VersionConverter struct {
+	// Decode decodes the merged tree into the manifest type of the version,
+	// its json fields are checked for unknown keys in strict mode
+	Decode func(tree map[string]interface{}) (interface{}, error)
+	// Upgrade converts the decoded manifest into the Application of LatestAPIVersion,
+	// the decoded value is used as is when nil
+	Upgrade func(v interface{}) (*Application, error)
+}
+
+var (
+	versionsMu sync.RWMutex
+	versions   = map[string]map[string]VersionConverter{
+		KindApplication: {
+			APIVersionV1: {
+				Decode: func(tree map[string]interface{}) (interface{}, error) {
+					return decodeApplication(tree)
+				},
+			},
+		},
+	}
+)
+
+// RegisterVersion registers the converter of the manifests of kind in apiVersion, so that older
+// manifests keep loading once the Application type has moved on
+func RegisterVersion(kind, apiVersion string, converter VersionConverter) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	if versions[kind] == nil {
+		versions[kind] = map[string]VersionConverter{}
+	}
+	versions[kind][apiVersion] = converter
+}
+
+// APIVersions returns the sorted api versions registered for kind
+func APIVersions(kind string) []string {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	apiVersions := make([]string, 0, len(versions[kind]))
+	for apiVersion := range versions[kind] {
+		apiVersions = append(apiVersions, apiVersion)
+	}
+	sort.Strings(apiVersions)
+
+	return apiVersions
+}
+
+func getVersion(kind, apiVersion string) (VersionConverter, bool) {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	converter, ok := versions[kind][apiVersion]
+	return converter, ok
+}
+
+// convert decodes tree with the converter of its kind and api_version, Application and LatestAPIVersion
+// when missing, and upgrades it. The decoded manifest is returned along the Application.
+func convert(tree map[string]interface{}) (*Application, interface{}, error) {
+	kind, _ := tree["kind"].(string)
+	apiVersion, _ := tree["api_version"].(string)
+	lookupKind, lookupVersion := kind, apiVersion
+	if lookupKind == "" {
+		lookupKind = KindApplication
+	}
+	if lookupVersion == "" {
+		lookupVersion = LatestAPIVersion
+	}
+
+	converter, ok := getVersion(lookupKind, lookupVersion)
+	if !ok {
+		known := APIVersions(lookupKind)
+		if len(known) == 0 {
+			return nil, nil, fmt.Errorf("aconfig: unknown kind %q", lookupKind)
+		}
+		return nil, nil, fmt.Errorf("aconfig: unknown api version %q of kind %q, known versions: %s",
+			lookupVersion, lookupKind, strings.Join(known, ", "))
+	}
+
+	decoded, err := converter.Decode(tree)
+	if err != nil {
+		return nil, nil, fmt.Errorf("aconfig: decode %s %s: %v", lookupKind, lookupVersion, err)
+	}
+
+	var application *Application
+	if converter.Upgrade != nil {
+		if application, err = converter.Upgrade(decoded); err != nil {
+			return nil, nil, fmt.Errorf("aconfig: upgrade %s %s to %s: %v", lookupKind, lookupVersion, LatestAPIVersion, err)
+		}
+	} else if application, ok = decoded.(*Application); !ok {
+		return nil, nil, fmt.Errorf("aconfig: %s %s decodes into %T without upgrade", lookupKind, lookupVersion, decoded)
+	}
+	if kind != "" {
+		application.Kind = KindApplication
+	}
+	if apiVersion != "" {
+		application.APIVersion = LatestAPIVersion
+	}
+
+	return application, decoded, nil
+}
+
+// DecodeTree decodes tree into out by its json fields, for the Decode of VersionConverters
+func DecodeTree(tree map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+func decodeApplication(tree map[string]interface{}) (*Application, error) {
+	var application = &Application{}
+	if err := DecodeTree(tree, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
@@ -0,0 +1,101 @@
+package aconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// legacyApplication is a manifest of the test api version v0, whose database is a flat dsn-like block
+type legacyApplication struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+	Database   struct {
+		Host string `json:"host"`
+		User string `json:"user"`
+		Name string `json:"name"`
+	} `json:"database"`
+}
+
+func init() {
+	RegisterVersion(KindApplication, "v0", VersionConverter{
+		Decode: func(tree map[string]interface{}) (interface{}, error) {
+			legacy := &legacyApplication{}
+			return legacy, DecodeTree(tree, legacy)
+		},
+		Upgrade: func(v interface{}) (*Application, error) {
+			legacy := v.(*legacyApplication)
+			return &Application{
+				TypeMeta:   legacy.TypeMeta,
+				ObjectMeta: legacy.ObjectMeta,
+				Spec: ApplicationSpec{SecondaryPorts: map[PortName]SecondaryPort{
+					"mysql": {
+						Interface:          Interface{Name: "mysql"},
+						Options:            KV{"user": legacy.Database.User, "database": legacy.Database.Name},
+						MatchedPrimaryPort: &MatchedPrimaryPort{Location: &Location{Address: legacy.Database.Host}},
+					},
+				}},
+			}, nil
+		},
+	})
+}
+
+const legacyManifest = `kind: Application
+api_version: v0
+metadata:
+  name: legacy
+database:
+  host: 10.0.0.1
+  user: app
+  name: orders
+`
+
+func TestLoadUpgradesOlderVersions(t *testing.T) {
+	loader := &Loader{Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+	application, err := loader.LoadData("app.yaml", []byte(legacyManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sp := application.Spec.SecondaryPorts["mysql"]
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"api version", application.APIVersion, LatestAPIVersion},
+		{"kind", application.Kind, KindApplication},
+		{"name", application.Name, "legacy"},
+		{"user", sp.Options.GetString("user"), "app"},
+		{"database", sp.Options.GetString("database"), "orders"},
+		{"address", sp.MatchedPrimaryPort.Location.Address, "10.0.0.1"},
+		{"defaulted port", sp.MatchedPrimaryPort.Location.Port, 3306},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if err = application.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadVersionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{name: "unknown kind", manifest: strings.Replace(validManifest, "kind: Application", "kind: Service", 1), want: `unknown kind "Service"`},
+		{name: "unknown api version", manifest: strings.Replace(validManifest, "api_version: v1", "api_version: v9", 1), want: `unknown api version "v9" of kind "Application", known versions: v0, v1`},
+		{name: "unknown field of the older version", manifest: legacyManifest + "spec: {}\n", want: "spec: unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := &Loader{Strict: true, DisableEnvOverlay: true, DisableSecretResolving: true}
+			if _, err := loader.LoadData("app.yaml", []byte(tt.manifest)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadData() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
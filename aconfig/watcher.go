@@ -0,0 +1,228 @@
+package aconfig
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alphaframework/alpha/alog"
+)
+
+const (
+	defaultWatchInterval = 5 * time.Second
+)
+
+type WatchOptions struct {
+	// Interval between two checks of the config files
+	Interval time.Duration
+	// Validate rejects a loaded Application by returning an error, (*Application).Validate if nil
+	Validate func(*Application) error
+}
+
+func (o *WatchOptions) complete() {
+	if o.Interval <= 0 {
+		o.Interval = defaultWatchInterval
+	}
+	if o.Validate == nil {
+		o.Validate = (*Application).Validate
+	}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls the files of a Loader and atomically swaps the Application when they change.
+// Invalid reloads are logged and the running Application is kept.
+type Watcher struct {
+	loader  *Loader
+	options WatchOptions
+	current atomic.Value
+	// reloadMu serializes the reloads
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	stamps      map[string]fileStamp
+	subscribers []func(old, new *Application)
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Watch loads configFile like New in strict mode and watches it for changes,
+// the reloads with unknown fields or failing validation are rejected
+func Watch(configFile string, options *WatchOptions, funcs ...PreProcessFunc) (*Watcher, error) {
+	loader := &Loader{
+		Files:           []string{configFile},
+		PreProcessFuncs: funcs,
+		Strict:          true,
+	}
+
+	return NewWatcher(loader, options)
+}
+
+func NewWatcher(loader *Loader, options *WatchOptions) (*Watcher, error) {
+	if options == nil {
+		options = &WatchOptions{}
+	}
+	options.complete()
+
+	w := &Watcher{
+		loader:  loader,
+		options: *options,
+		stop:    make(chan struct{}),
+	}
+
+	w.stamps = w.stat()
+	application, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(application)
+
+	go w.run()
+
+	return w, nil
+}
+
+// Application returns the current Application, it must not be modified
+func (w *Watcher) Application() *Application {
+	return w.current.Load().(*Application)
+}
+
+// OnChange registers fn to be called after every successful reload
+func (w *Watcher) OnChange(fn func(old, new *Application)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// OnSecondaryPortChange registers fn to be called when the secondary port name changes,
+// old or new is nil when the port is added or removed
+func (w *Watcher) OnSecondaryPortChange(name PortName, fn func(old, new *SecondaryPort)) {
+	w.OnChange(func(oldApp, newApp *Application) {
+		oldPort, newPort := oldApp.GetSecondaryPort(name), newApp.GetSecondaryPort(name)
+		if !reflect.DeepEqual(oldPort, newPort) {
+			fn(oldPort, newPort)
+		}
+	})
+}
+
+// OnCustomConfigChange registers fn to be called when the custom config key changes,
+// old or new is nil when the key is added or removed
+func (w *Watcher) OnCustomConfigChange(key string, fn func(old, new interface{})) {
+	w.OnChange(func(oldApp, newApp *Application) {
+		oldValue, newValue := oldApp.GetCustomConfig().Get(key), newApp.GetCustomConfig().Get(key)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			fn(oldValue, newValue)
+		}
+	})
+}
+
+// Reload loads the files now regardless of their modification
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	w.stamps = w.stat()
+	w.mu.Unlock()
+
+	return w.reload()
+}
+
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+func (w *Watcher) run() {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+			if err := w.reload(); err != nil {
+				logWatchError("aconfig.Watcher: reload rejected, keep running config: %v", err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) changed() bool {
+	stamps := w.stat()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if reflect.DeepEqual(stamps, w.stamps) {
+		return false
+	}
+	w.stamps = stamps
+
+	return true
+}
+
+func (w *Watcher) stat() map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(w.loader.Files))
+	for _, file := range w.loader.Files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return stamps
+}
+
+func (w *Watcher) load() (*Application, error) {
+	application, err := w.loader.Load()
+	if err != nil {
+		return nil, err
+	}
+	if err = w.options.Validate(application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+func (w *Watcher) reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	application, err := w.load()
+	if err != nil {
+		return err
+	}
+
+	old := w.Application()
+	w.current.Store(application)
+
+	w.mu.Lock()
+	subscribers := make([]func(old, new *Application), len(w.subscribers))
+	copy(subscribers, w.subscribers)
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, application)
+	}
+
+	return nil
+}
+
+func logWatchError(template string, args ...interface{}) {
+	if alog.Sugar == nil {
+		return
+	}
+	alog.Sugar.Errorf(template, args...)
+}
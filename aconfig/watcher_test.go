@@ -0,0 +1,77 @@
+package aconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const validManifest = `kind: Application
+api_version: v1
+metadata:
+  name: demo
+spec:
+  secondary_ports:
+    mysql:
+      interface:
+        name: mysql
+      options:
+        user: root
+        database: demo
+      matched_primary_port:
+        location:
+          address: 127.0.0.1
+`
+
+func TestWatcherRejectsInvalidReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.yaml")
+	if err = ioutil.WriteFile(file, []byte(validManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Watch(file, &WatchOptions{Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"unknown field", validManifest + "  custom_confg: {}\n"},
+		{"missing location", `kind: Application
+api_version: v1
+metadata:
+  name: demo
+spec:
+  secondary_ports:
+    mysql:
+      interface:
+        name: mysql
+      options:
+        user: root
+        database: demo
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ioutil.WriteFile(file, []byte(tt.manifest), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Reload(); err == nil {
+				t.Errorf("Reload accepted an invalid config")
+			}
+			if w.Application().GetSecondaryPort("mysql").MatchedPrimaryPort.Location == nil {
+				t.Errorf("the running Application was replaced")
+			}
+		})
+	}
+}
@@ -31,79 +31,79 @@ const (
 )
 
 func ErrUnknown(messages ...string) *Error {
-	return New(CodeUnknown).WithHttpStatusCode(500).WithMessages(messages...)
+	return New(CodeUnknown).WithMessages(messages...)
 }
 
 func ErrUnauthorized(messages ...string) *Error {
-	return New(CodeUnauthorized).WithHttpStatusCode(401).WithMessages(messages...)
+	return New(CodeUnauthorized).WithMessages(messages...)
 }
 
 func ErrForbidden(messages ...string) *Error {
-	return New(CodeForbidden).WithHttpStatusCode(403).WithMessages(messages...)
+	return New(CodeForbidden).WithMessages(messages...)
 }
 
 func ErrNotFound(messages ...string) *Error {
-	return New(CodeNotFound).WithHttpStatusCode(404).WithMessages(messages...)
+	return New(CodeNotFound).WithMessages(messages...)
 }
 
 func ErrAlreadyExists(messages ...string) *Error {
-	return New(CodeAlreadyExists).WithHttpStatusCode(409).WithMessages(messages...)
+	return New(CodeAlreadyExists).WithMessages(messages...)
 }
 
 func ErrConflict(messages ...string) *Error {
-	return New(CodeConflict).WithHttpStatusCode(409).WithMessages(messages...)
+	return New(CodeConflict).WithMessages(messages...)
 }
 
 func ErrGone(messages ...string) *Error {
-	return New(CodeGone).WithHttpStatusCode(410).WithMessages(messages...)
+	return New(CodeGone).WithMessages(messages...)
 }
 
 func ErrInvalid(messages ...string) *Error {
-	return New(CodeInvalid).WithHttpStatusCode(422).WithMessages(messages...)
+	return New(CodeInvalid).WithMessages(messages...)
 }
 
 func ErrServerTimeout(messages ...string) *Error {
-	return New(CodeServerTimeout).WithHttpStatusCode(500).WithMessages(messages...)
+	return New(CodeServerTimeout).WithMessages(messages...)
 }
 
 func ErrTimeout(messages ...string) *Error {
-	return New(CodeTimeout).WithHttpStatusCode(504).WithMessages(messages...)
+	return New(CodeTimeout).WithMessages(messages...)
 }
 
 func ErrTooManyRequests(messages ...string) *Error {
-	return New(CodeTooManyRequests).WithHttpStatusCode(429).WithMessages(messages...)
+	return New(CodeTooManyRequests).WithMessages(messages...)
 }
 
 func ErrBadRequest(messages ...string) *Error {
-	return New(CodeBadRequest).WithHttpStatusCode(400).WithMessages(messages...)
+	return New(CodeBadRequest).WithMessages(messages...)
 }
 
 func ErrMethodNotAllowed(messages ...string) *Error {
-	return New(CodeMethodNotAllowed).WithHttpStatusCode(405).WithMessages(messages...)
+	return New(CodeMethodNotAllowed).WithMessages(messages...)
 }
 
 func ErrNotAcceptable(messages ...string) *Error {
-	return New(CodeNotAcceptable).WithHttpStatusCode(406).WithMessages(messages...)
+	return New(CodeNotAcceptable).WithMessages(messages...)
 }
 
 func ErrRequestEntityTooLarge(messages ...string) *Error {
-	return New(CodeRequestEntityTooLarge).WithHttpStatusCode(413).WithMessages(messages...)
+	return New(CodeRequestEntityTooLarge).WithMessages(messages...)
 }
 
 func ErrUnsupportedMediaType(messages ...string) *Error {
-	return New(CodeUnsupportedMediaType).WithHttpStatusCode(415).WithMessages(messages...)
+	return New(CodeUnsupportedMediaType).WithMessages(messages...)
 }
 
 func ErrInternalError(messages ...string) *Error {
-	return New(CodeInternalError).WithHttpStatusCode(500).WithMessages(messages...)
+	return New(CodeInternalError).WithMessages(messages...)
 }
 
 func ErrExpired(messages ...string) *Error {
-	return New(CodeExpired).WithHttpStatusCode(410).WithMessages(messages...)
+	return New(CodeExpired).WithMessages(messages...)
 }
 
 func ErrServiceUnavailable(messages ...string) *Error {
-	return New(CodeServiceUnavailable).WithHttpStatusCode(503).WithMessages(messages...)
+	return New(CodeServiceUnavailable).WithMessages(messages...)
 }
 
 type Details map[string]interface{}
@@ -117,13 +117,26 @@ type errorPayload struct {
 type Error struct {
 	Err            errorPayload `json:"error,omitempty"`
 	HTTPStatusCode int          `json:"-"`
+
+	// cause is reported by Error and Unwrap, never serialized
+	cause error
+	// messageID and params select the message of the catalogs, see Localize
+	messageID string
+	params    map[string]interface{}
+	// stack is captured on creation when enabled by SetStackCapture
+	stack []uintptr
+	// definition is the Definition creating the Error
+	definition *Definition
 }
 
+// New returns an Error of code with the HTTP status code registered for it
 func New(code Code) *Error {
 	return &Error{
 		Err: errorPayload{
 			Code: code,
 		},
+		HTTPStatusCode: InfoOf(code).HTTPStatusCode,
+		stack:          callers(),
 	}
 }
 
@@ -135,24 +148,99 @@ func NewWithAll(code Code, message string, details Details, httpStatusCode int)
 			Details: details,
 		},
 		HTTPStatusCode: httpStatusCode,
+		stack:          callers(),
 	}
 }
 
+// UnmarshallJSON decodes the json of an Error, or of a problem document whose type starts with ProblemTypePrefix.
+// The status is the status member of the problem, the status of the errors of an aggregate,
+// or the one registered for the code, see UnmarshallResponseJSON for the codes registered elsewhere.
 func UnmarshallJSON(data []byte) (*Error, bool) {
+	return UnmarshallResponseJSON(0, data)
+}
+
+// UnmarshallResponseJSON decodes the body of a response like UnmarshallJSON, the status of the response
+// is used when the body has none, before the status registered for the code
+func UnmarshallResponseJSON(statusCode int, data []byte) (*Error, bool) {
 	ae := &Error{}
 	if err := json.Unmarshal(data, ae); err != nil {
 		return nil, false
 	}
 
 	if len(ae.Err.Code) == 0 {
-		return nil, false
+		p := &Problem{}
+		if err := json.Unmarshal(data, p); err != nil || !strings.HasPrefix(p.Type, ProblemTypePrefix) {
+			return nil, false
+		}
+		if p.Status == 0 {
+			p.Status = statusCode
+		}
+		ae = p.ToError()
+		return ae, ae != nil
+	}
+	if m, ok := MultiErrorOf(ae); ok && len(m.Errors) > 0 {
+		ae.HTTPStatusCode = m.HTTPStatusCode()
+	} else if statusCode != 0 {
+		ae.HTTPStatusCode = statusCode
+	} else {
+		ae.HTTPStatusCode = InfoOf(ae.Err.Code).HTTPStatusCode
 	}
 
 	return ae, true
 }
 
+// Wrap returns an Error of code and message caused by err, nil if err is nil
+func Wrap(err error, code Code, message string) *Error {
+	if err == nil {
+		return nil
+	}
+
+	return New(code).WithMessage(message).WithCause(err)
+}
+
+func Wrapf(err error, code Code, format string, a ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+
+	return Wrap(err, code, fmt.Sprintf(format, a...))
+}
+
+// Error returns the message followed by the cause
 func (ae *Error) Error() string {
-	return ae.Err.Message
+	if ae.cause == nil {
+		return ae.Err.Message
+	}
+
+	cause := ae.cause.Error()
+	if ae.Err.Message == "" || ae.Err.Message == cause {
+		return cause
+	}
+
+	return ae.Err.Message + ": " + cause
+}
+
+func (ae *Error) Unwrap() error {
+	return ae.cause
+}
+
+// Is reports whether target is an *Error with the same Code, e.g. errors.Is(err, aerror.ErrNotFound()),
+// or the Definition of ae
+func (ae *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case *Error:
+		return t.Err.Code != "" && t.Err.Code == ae.Err.Code
+	case *Definition:
+		return ae.definition == t
+	}
+
+	return false
+}
+
+func (ae *Error) WithCause(err error) *Error {
+	ae.cause = err
+
+	return ae
 }
 
 func (ae *Error) WithMessage(message string) *Error {
@@ -191,23 +279,12 @@ func (ae *Error) WithError(err error) *Error {
 		*ae = *e
 	} else {
 		ae.Err.Message = err.Error()
+		ae.cause = err
 	}
 
 	return ae
 }
 
 func IsNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	e, ok := err.(*Error)
-	if !ok {
-		return false
-	}
-	if e.Err.Code == CodeNotFound {
-		return true
-	}
-
-	return false
+	return Is(err, CodeNotFound)
 }
@@ -0,0 +1,86 @@
+package aerror
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnmarshallJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		ok     bool
+		code   Code
+		status int
+	}{
+		{"error", `{"error":{"code":"not_found","message":"missing"}}`, true, CodeNotFound, 404},
+		{"problem", `{"type":"urn:alpha:error:gone","status":410,"detail":"gone"}`, true, CodeGone, 410},
+		{"foreign problem", `{"type":"https://example.com/probs/out-of-credit","code":"credit"}`, false, "", 0},
+		{"success code", `{"code":"ok","data":{"id":1}}`, false, "", 0},
+		{"success numeric code", `{"code":"0","msg":"success"}`, false, "", 0},
+		{"empty object", `{}`, false, "", 0},
+		{"not json", `oops`, false, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae, ok := UnmarshallJSON([]byte(tt.data))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if ae.Err.Code != tt.code || ae.HTTPStatusCode != tt.status {
+				t.Errorf("got %s %d, want %s %d", ae.Err.Code, ae.HTTPStatusCode, tt.code, tt.status)
+			}
+		})
+	}
+}
+
+func TestUnmarshallProblemJSON(t *testing.T) {
+	ae, ok := UnmarshallProblemJSON([]byte(`{"type":"about:blank","code":"conflict","detail":"busy","retry":true}`))
+	if !ok || ae.Err.Code != CodeConflict || ae.Err.Message != "busy" || ae.Err.Details["retry"] != true {
+		t.Errorf("got %v %+v", ok, ae)
+	}
+}
+
+func TestUnmarshallResponseJSONStatus(t *testing.T) {
+	aggregate := (&MultiError{Errors: []*Error{ErrNotFound("a"), ErrConflict("b")}}).ToError()
+	aggregateData, err := json.Marshal(aggregate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameStatus := (&MultiError{Errors: []*Error{ErrConflict("a"), ErrAlreadyExists("b")}}).ToError()
+	sameStatusData, err := json.Marshal(sameStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		data       string
+		code       Code
+		status     int
+	}{
+		{"registered code", 0, `{"error":{"code":"not_found"}}`, CodeNotFound, 404},
+		{"unregistered code", 0, `{"error":{"code":"quota_exceeded"}}`, "quota_exceeded", 500},
+		{"unregistered code of the response", 402, `{"error":{"code":"quota_exceeded"}}`, "quota_exceeded", 402},
+		{"status of the response", 404, `{"error":{"code":"not_found"}}`, CodeNotFound, 404},
+		{"aggregate", 0, string(aggregateData), CodeMultiple, 400},
+		{"aggregate sharing a status", 0, string(sameStatusData), CodeMultiple, 409},
+		{"problem status", 503, `{"type":"urn:alpha:error:quota_exceeded","status":429}`, "quota_exceeded", 429},
+		{"problem without status", 402, `{"type":"urn:alpha:error:quota_exceeded"}`, "quota_exceeded", 402},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae, ok := UnmarshallResponseJSON(tt.statusCode, []byte(tt.data))
+			if !ok {
+				t.Fatalf("UnmarshallResponseJSON() failed")
+			}
+			if ae.Err.Code != tt.code || ae.HTTPStatusCode != tt.status {
+				t.Errorf("got %s %d, want %s %d", ae.Err.Code, ae.HTTPStatusCode, tt.code, tt.status)
+			}
+		})
+	}
+}
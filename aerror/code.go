@@ -0,0 +1,138 @@
+package aerror
+
+import (
+	"errors"
+	"sync"
+)
+
+// CanonicalCode is a gRPC canonical status code
+type CanonicalCode int
+
+const (
+	CanonicalOK CanonicalCode = iota
+	CanonicalCancelled
+	CanonicalUnknown
+	CanonicalInvalidArgument
+	CanonicalDeadlineExceeded
+	CanonicalNotFound
+	CanonicalAlreadyExists
+	CanonicalPermissionDenied
+	CanonicalResourceExhausted
+	CanonicalFailedPrecondition
+	CanonicalAborted
+	CanonicalOutOfRange
+	CanonicalUnimplemented
+	CanonicalInternal
+	CanonicalUnavailable
+	CanonicalDataLoss
+	CanonicalUnauthenticated
+)
+
+var canonicalCodeNames = [...]string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS",
+	"PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE",
+	"UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+func (c CanonicalCode) String() string {
+	if c < 0 || int(c) >= len(canonicalCodeNames) {
+		return "UNKNOWN"
+	}
+
+	return canonicalCodeNames[c]
+}
+
+// CodeInfo describes the defaults of a Code
+type CodeInfo struct {
+	HTTPStatusCode int
+	CanonicalCode  CanonicalCode
+	// Retryable tells whether the failed request may succeed when retried as is
+	Retryable bool
+}
+
+// unknownCodeInfo applies to the codes not registered
+var unknownCodeInfo = CodeInfo{HTTPStatusCode: 500, CanonicalCode: CanonicalUnknown}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[Code]CodeInfo{
+		CodeUnknown:               {500, CanonicalUnknown, false},
+		CodeUnauthorized:          {401, CanonicalUnauthenticated, false},
+		CodeForbidden:             {403, CanonicalPermissionDenied, false},
+		CodeNotFound:              {404, CanonicalNotFound, false},
+		CodeAlreadyExists:         {409, CanonicalAlreadyExists, false},
+		CodeConflict:              {409, CanonicalAborted, true},
+		CodeGone:                  {410, CanonicalNotFound, false},
+		CodeInvalid:               {422, CanonicalInvalidArgument, false},
+		CodeServerTimeout:         {500, CanonicalUnavailable, true},
+		CodeTimeout:               {504, CanonicalDeadlineExceeded, true},
+		CodeTooManyRequests:       {429, CanonicalResourceExhausted, true},
+		CodeBadRequest:            {400, CanonicalInvalidArgument, false},
+		CodeMethodNotAllowed:      {405, CanonicalUnimplemented, false},
+		CodeNotAcceptable:         {406, CanonicalInvalidArgument, false},
+		CodeRequestEntityTooLarge: {413, CanonicalOutOfRange, false},
+		CodeUnsupportedMediaType:  {415, CanonicalInvalidArgument, false},
+		CodeInternalError:         {500, CanonicalInternal, false},
+		CodeExpired:               {410, CanonicalFailedPrecondition, false},
+		CodeServiceUnavailable:    {503, CanonicalUnavailable, true},
+		CodeMultiple:              {500, CanonicalUnknown, false},
+	}
+)
+
+// RegisterCode registers the defaults of an application code, or overrides those of a builtin one
+func RegisterCode(code Code, info CodeInfo) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	codes[code] = info
+}
+
+// LookupCode returns the CodeInfo of code, ok is false for the codes not registered
+func LookupCode(code Code) (info CodeInfo, ok bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	info, ok = codes[code]
+	return info, ok
+}
+
+// InfoOf returns the CodeInfo of code, 500 and CanonicalUnknown if not registered
+func InfoOf(code Code) CodeInfo {
+	if info, ok := LookupCode(code); ok {
+		return info
+	}
+
+	return unknownCodeInfo
+}
+
+// Is reports whether an *Error of code is in the chain of err
+func Is(err error, code Code) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, New(code))
+}
+
+// CodeOf returns the code of the first *Error in the chain of err, CodeUnknown if none
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+
+	var ae *Error
+	if errors.As(err, &ae) && ae.Err.Code != "" {
+		return ae.Err.Code
+	}
+
+	return CodeUnknown
+}
+
+// IsRetryable reports whether the code of err is registered as retryable
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return InfoOf(CodeOf(err)).Retryable
+}
@@ -0,0 +1,152 @@
+package aerror
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Definition is an immutable template of Errors, to be declared once at package level:
+//
+//	var ErrUserMissing = aerror.Define(aerror.CodeNotFound, "user %d not found")
+//
+//	return ErrUserMissing.New(id)
+//
+// Each New returns a fresh Error which errors.Is reports equal to its Definition.
+type Definition struct {
+	code           Code
+	format         string
+	httpStatusCode int
+	messageID      string
+	details        Details
+}
+
+// Define returns the Definition of the Errors of code whose message is format, formatted with
+// the args of New when any
+func Define(code Code, format string) *Definition {
+	return &Definition{
+		code:           code,
+		format:         format,
+		httpStatusCode: InfoOf(code).HTTPStatusCode,
+	}
+}
+
+func (d *Definition) Code() Code {
+	return d.code
+}
+
+// Error makes d usable as the target of errors.Is
+func (d *Definition) Error() string {
+	return d.format
+}
+
+// WithHttpStatusCode returns a copy of d creating Errors of statusCode
+func (d *Definition) WithHttpStatusCode(statusCode int) *Definition {
+	c := *d
+	c.httpStatusCode = statusCode
+
+	return &c
+}
+
+// WithMessageID returns a copy of d whose Errors of NewWithParams take the catalog message messageID,
+// the Errors of New keep the format of d formatted with their args
+func (d *Definition) WithMessageID(messageID string) *Definition {
+	c := *d
+	c.messageID = messageID
+
+	return &c
+}
+
+// WithDetails returns a copy of d creating Errors with a copy of details, the maps and slices
+// of details are copied deeply so that changing them later affects neither d nor its Errors
+func (d *Definition) WithDetails(details Details) *Definition {
+	c := *d
+	c.details = copyDetails(details)
+
+	return &c
+}
+
+// New returns a fresh Error of d, its message is the format of d formatted with args
+func (d *Definition) New(args ...interface{}) *Error {
+	message := d.format
+	if len(args) > 0 {
+		message = fmt.Sprintf(d.format, args...)
+	}
+
+	return d.instance(message, nil)
+}
+
+// NewWithParams returns a fresh Error of d with its catalog message executed with params,
+// which must not be nil for the message id of d to apply
+func (d *Definition) NewWithParams(params map[string]interface{}) *Error {
+	return d.instance(d.format, params)
+}
+
+// Wrap returns a fresh Error of d caused by err, nil if err is nil
+func (d *Definition) Wrap(err error, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+
+	return d.New(args...).WithCause(err)
+}
+
+func (d *Definition) instance(message string, params map[string]interface{}) *Error {
+	ae := New(d.code).WithHttpStatusCode(d.httpStatusCode).WithMessage(message)
+	ae.definition = d
+	if len(d.details) > 0 {
+		ae.Err.Details = copyDetails(d.details)
+	}
+	if d.messageID != "" && params != nil {
+		ae.WithMessageID(d.messageID, params)
+	}
+
+	return ae
+}
+
+func copyDetails(details Details) Details {
+	if details == nil {
+		return nil
+	}
+
+	return deepCopy(reflect.ValueOf(details)).Interface().(Details)
+}
+
+// deepCopy copies the maps, slices and arrays of v, the other values are shared
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	}
+
+	return v
+}
@@ -0,0 +1,69 @@
+package aerror
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefinitionDetailsAreCopied(t *testing.T) {
+	details := Details{"ids": []interface{}{1}, "nested": map[string]interface{}{"k": "v"}}
+	d := Define(CodeNotFound, "user %d not found").WithDetails(details)
+
+	details["added"] = true
+	details["nested"].(map[string]interface{})["k"] = "changed"
+
+	first := d.New(1)
+	first.Err.Details["ids"].([]interface{})[0] = 2
+	first.Err.Details["nested"].(map[string]interface{})["k"] = "mutated"
+
+	second := d.New(2)
+	if _, ok := second.Err.Details["added"]; ok {
+		t.Errorf("details changed after WithDetails leaked into %v", second.Err.Details)
+	}
+	if got := second.Err.Details["nested"].(map[string]interface{})["k"]; got != "v" {
+		t.Errorf("nested = %v, want v", got)
+	}
+	if got := second.Err.Details["ids"].([]interface{})[0]; got != 1 {
+		t.Errorf("ids[0] = %v, want 1", got)
+	}
+	if second.Err.Message != "user 2 not found" {
+		t.Errorf("message = %q", second.Err.Message)
+	}
+}
+
+func TestDefinitionIs(t *testing.T) {
+	d := Define(CodeNotFound, "missing")
+	other := Define(CodeNotFound, "missing")
+
+	err := d.New()
+	if !errors.Is(err, d) || errors.Is(err, other) || !errors.Is(err, ErrNotFound()) {
+		t.Errorf("errors.Is does not compare %v to its definition", err)
+	}
+}
+
+func TestDefinitionMessageID(t *testing.T) {
+	if err := RegisterMessages("en-US", map[string]string{"not_found.definition_user": "user {{.id}} is missing"}); err != nil {
+		t.Fatal(err)
+	}
+	d := Define(CodeNotFound, "user %d not found").WithMessageID("definition_user")
+
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{name: "New", err: d.New(7), want: "user 7 not found"},
+		{name: "NewWithParams", err: d.NewWithParams(map[string]interface{}{"id": 7}), want: "user 7 is missing"},
+		{name: "Wrap", err: d.Wrap(errors.New("cause"), 7), want: "user 7 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Err.Message; got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+			if got := tt.err.Localize("en-US").Err.Message; got != tt.want {
+				t.Errorf("localized message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package aerror
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var (
+	catalogsMu      sync.RWMutex
+	catalogs        = map[string]map[string]*template.Template{}
+	defaultLanguage = "en-US"
+)
+
+// MessageKey is the catalog key of the message id of code, the code alone for its default message
+func MessageKey(code Code, messageID string) string {
+	if messageID == "" {
+		return string(code)
+	}
+
+	return string(code) + "." + messageID
+}
+
+// RegisterMessages adds the messages of language keyed by MessageKey, they are text/template
+// templates executed with the params of the Error, e.g. "user {{.id}} not found"
+func RegisterMessages(language string, messages map[string]string) error {
+	parsed := make(map[string]*template.Template, len(messages))
+	for key, message := range messages {
+		t, err := template.New(key).Parse(message)
+		if err != nil {
+			return err
+		}
+		parsed[key] = t
+	}
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	language = strings.ToLower(language)
+	if catalogs[language] == nil {
+		catalogs[language] = map[string]*template.Template{}
+	}
+	for key, t := range parsed {
+		catalogs[language][key] = t
+	}
+
+	return nil
+}
+
+// SetDefaultLanguage sets the language of Err.Message and the fallback of Localize, en-US by default
+func SetDefaultLanguage(language string) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	defaultLanguage = language
+}
+
+func DefaultLanguage() string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	return defaultLanguage
+}
+
+func message(language, key string, params map[string]interface{}) (string, bool) {
+	catalogsMu.RLock()
+	t, ok := catalogs[strings.ToLower(language)][key]
+	catalogsMu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		return "", false
+	}
+
+	return buf.String(), true
+}
+
+// WithMessageID sets the catalog message of ae, Err.Message is set to the message in the default language
+func (ae *Error) WithMessageID(messageID string, params map[string]interface{}) *Error {
+	ae.messageID = messageID
+	ae.params = params
+	if m, ok := message(DefaultLanguage(), MessageKey(ae.Err.Code, messageID), params); ok {
+		ae.Err.Message = m
+	}
+
+	return ae
+}
+
+// Localize returns a copy of ae whose message is in language, or in the default language when
+// the catalog of language misses it. The message is left as is for the errors without message id
+// unless empty, which takes the message of the code.
+func (ae *Error) Localize(language string) *Error {
+	if ae.messageID == "" && ae.Err.Message != "" {
+		return ae
+	}
+
+	key := MessageKey(ae.Err.Code, ae.messageID)
+	m, ok := message(language, key, ae.params)
+	if !ok {
+		if m, ok = message(DefaultLanguage(), key, ae.params); !ok {
+			return ae
+		}
+	}
+
+	localized := *ae
+	localized.Err.Message = m
+	return &localized
+}
+
+// MatchLanguage returns the registered language best matching an Accept-Language header,
+// the default language if none
+func MatchLanguage(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, t := range tags {
+		if _, ok := catalogs[t.tag]; ok {
+			return t.tag
+		}
+		// zh matches zh-cn, and zh-tw falls back to zh
+		base := strings.Split(t.tag, "-")[0]
+		for _, language := range languages {
+			if language == base || strings.Split(language, "-")[0] == base {
+				return language
+			}
+		}
+	}
+
+	return defaultLanguage
+}
@@ -0,0 +1,88 @@
+package aerror
+
+import "testing"
+
+const codeLocalized Code = "localized"
+
+func init() {
+	messages := map[string]map[string]string{
+		"en-US": {
+			"localized":              "localized error",
+			"localized.user_missing": "user {{.id}} not found",
+		},
+		"zh-CN": {
+			"localized.user_missing": "用户 {{.id}} 不存在",
+		},
+		"fr": {
+			"localized.quota": "quota de {{.user}} dépassé",
+		},
+	}
+	for language, m := range messages {
+		if err := RegisterMessages(language, m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "en-US"},
+		{"*", "en-US"},
+		{"de", "en-US"},
+		{"zh-CN", "zh-cn"},
+		{"ZH-cn", "zh-cn"},
+		{"zh", "zh-cn"},
+		{"zh-TW", "zh-cn"},
+		{"fr-CA, en;q=0.5", "fr"},
+		{"de, en-US;q=0.8, fr;q=0.7", "en-us"},
+		{"fr;q=0.2, zh;q=0.9", "zh-cn"},
+		{"fr;q=0, de", "en-US"},
+	}
+	for _, tt := range tests {
+		if got := MatchLanguage(tt.acceptLanguage); got != tt.want {
+			t.Errorf("MatchLanguage(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	params := map[string]interface{}{"id": 7, "user": "ann"}
+
+	tests := []struct {
+		name     string
+		err      *Error
+		language string
+		want     string
+	}{
+		{name: "language of the catalog", err: New(codeLocalized).WithMessageID("user_missing", params), language: "zh-cn", want: "用户 7 不存在"},
+		{name: "matched language", err: New(codeLocalized).WithMessageID("user_missing", params), language: MatchLanguage("zh-TW"), want: "用户 7 不存在"},
+		{name: "fallback to the default language", err: New(codeLocalized).WithMessageID("user_missing", params), language: "fr", want: "user 7 not found"},
+		{name: "unknown language", err: New(codeLocalized).WithMessageID("user_missing", params), language: "de", want: "user 7 not found"},
+		{name: "only in another language", err: New(codeLocalized).WithMessage("over quota").WithMessageID("quota", params), language: "en-US", want: "over quota"},
+		{name: "missing message id", err: New(codeLocalized).WithMessage("as is").WithMessageID("missing", params), language: "zh-cn", want: "as is"},
+		{name: "message without id", err: New(codeLocalized).WithMessage("as is"), language: "en-US", want: "as is"},
+		{name: "message of the code", err: New(codeLocalized), language: "zh-cn", want: "localized error"},
+		{name: "code without message", err: New("not_localized"), language: "en-US", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.err.Err.Message
+			if got := tt.err.Localize(tt.language).Err.Message; got != tt.want {
+				t.Errorf("Localize(%q) = %q, want %q", tt.language, got, tt.want)
+			}
+			if tt.err.Err.Message != before {
+				t.Errorf("Localize() changed the message of the Error to %q", tt.err.Err.Message)
+			}
+		})
+	}
+}
+
+func TestWithMessageIDDefaultLanguage(t *testing.T) {
+	ae := New(codeLocalized).WithMessageID("user_missing", map[string]interface{}{"id": 7})
+	if ae.Err.Message != "user 7 not found" {
+		t.Errorf("message = %q, want the message of the default language", ae.Err.Message)
+	}
+}
@@ -0,0 +1,181 @@
+package aerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// CodeMultiple is the code of the aggregates of errors with different codes
+	CodeMultiple Code = "multiple"
+	// DetailsKeyErrors is the key of the sub-errors of an aggregate in the Details
+	DetailsKeyErrors = "errors"
+)
+
+// MultiError collects several Errors, e.g. the failed items of a batch
+type MultiError struct {
+	Errors []*Error
+}
+
+// Append adds errs to m, the nil ones are skipped and those which are not *Error become internal errors
+func (m *MultiError) Append(errs ...error) *MultiError {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		switch e := err.(type) {
+		case *Error:
+			if e != nil {
+				m.Errors = append(m.Errors, e)
+			}
+		case *MultiError:
+			m.Errors = append(m.Errors, e.Errors...)
+		default:
+			m.Errors = append(m.Errors, ErrInternalError().WithError(err))
+		}
+	}
+
+	return m
+}
+
+func (m *MultiError) Len() int {
+	return len(m.Errors)
+}
+
+// ErrorOrNil returns m as an error, nil if empty
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.Errors) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+func (m *MultiError) Error() string {
+	messages := make([]string, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		messages = append(messages, e.Error())
+	}
+
+	return fmt.Sprintf("%d errors: %s", len(m.Errors), strings.Join(messages, "; "))
+}
+
+// Is reports whether target is in the chain of one of the errors
+func (m *MultiError) Is(target error) bool {
+	for _, e := range m.Errors {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTTPStatusCode returns the status shared by the errors, 400 when they are all client errors, 500 otherwise
+func (m *MultiError) HTTPStatusCode() int {
+	status := 0
+	clientErrors := true
+	for _, e := range m.Errors {
+		if status == 0 {
+			status = e.HTTPStatusCode
+		} else if status != e.HTTPStatusCode {
+			status = -1
+		}
+		if e.HTTPStatusCode < 400 || e.HTTPStatusCode > 499 {
+			clientErrors = false
+		}
+	}
+
+	switch {
+	case status > 0:
+		return status
+	case clientErrors:
+		return 400
+	default:
+		return 500
+	}
+}
+
+type subError struct {
+	Code    Code    `json:"code"`
+	Message string  `json:"message,omitempty"`
+	Details Details `json:"details,omitempty"`
+	Status  int     `json:"status,omitempty"`
+}
+
+// ToError returns the Error serializing the errors into its details, its code is the code shared by
+// the errors or CodeMultiple, nil if m is empty
+func (m *MultiError) ToError() *Error {
+	if len(m.Errors) == 0 {
+		return nil
+	}
+
+	code := m.Errors[0].Err.Code
+	subErrors := make([]subError, 0, len(m.Errors))
+	messages := make([]string, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		if e.Err.Code != code {
+			code = CodeMultiple
+		}
+		subErrors = append(subErrors, subError{
+			Code:    e.Err.Code,
+			Message: e.Err.Message,
+			Details: e.Err.Details,
+			Status:  e.HTTPStatusCode,
+		})
+		messages = append(messages, e.Err.Message)
+	}
+
+	return New(code).
+		WithHttpStatusCode(m.HTTPStatusCode()).
+		WithMessage(fmt.Sprintf("%d errors: %s", len(m.Errors), strings.Join(messages, "; "))).
+		WithDetails(Details{DetailsKeyErrors: subErrors}).
+		WithCause(m)
+}
+
+// SubErrors returns the errors serialized into the details of an aggregate, also when decoded by UnmarshallJSON
+func (ae *Error) SubErrors() []*Error {
+	v, ok := ae.Err.Details[DetailsKeyErrors]
+	if !ok {
+		return nil
+	}
+
+	var subErrors []subError
+	if s, ok := v.([]subError); ok {
+		subErrors = s
+	} else {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		if err = json.Unmarshal(data, &subErrors); err != nil {
+			return nil
+		}
+	}
+
+	errs := make([]*Error, 0, len(subErrors))
+	for _, s := range subErrors {
+		e := &Error{
+			Err:            errorPayload{Code: s.Code, Message: s.Message, Details: s.Details},
+			HTTPStatusCode: s.Status,
+		}
+		if e.HTTPStatusCode == 0 {
+			e.HTTPStatusCode = InfoOf(s.Code).HTTPStatusCode
+		}
+		errs = append(errs, e)
+	}
+
+	return errs
+}
+
+// MultiErrorOf returns the aggregate serialized into ae, false if ae is not an aggregate
+func MultiErrorOf(ae *Error) (*MultiError, bool) {
+	errs := ae.SubErrors()
+	if errs == nil {
+		return nil, false
+	}
+
+	return &MultiError{Errors: errs}, true
+}
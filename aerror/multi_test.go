@@ -0,0 +1,56 @@
+package aerror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestMultiErrorIs(t *testing.T) {
+	m := (&MultiError{}).Append(ErrNotFound(), Wrap(io.EOF, CodeInternalError, "read"))
+
+	for _, target := range []error{io.EOF, ErrNotFound(), ErrInternalError()} {
+		if !errors.Is(m, target) {
+			t.Errorf("errors.Is(m, %v) = false", target)
+		}
+	}
+	if errors.Is(m, io.ErrUnexpectedEOF) || errors.Is(m, ErrGone()) {
+		t.Errorf("errors.Is matched a foreign target")
+	}
+}
+
+func TestMultiErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []error
+		code   Code
+		status int
+	}{
+		{"same code", []error{ErrNotFound("a"), ErrNotFound("b")}, CodeNotFound, 404},
+		{"client errors", []error{ErrNotFound("a"), ErrInvalid("b")}, CodeMultiple, 400},
+		{"server error", []error{ErrNotFound("a"), errors.New("b")}, CodeMultiple, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := (&MultiError{}).Append(tt.errs...)
+			data, err := json.Marshal(m.ToError())
+			if err != nil {
+				t.Fatal(err)
+			}
+			ae, ok := UnmarshallJSON(data)
+			if !ok || ae.Err.Code != tt.code || m.HTTPStatusCode() != tt.status {
+				t.Fatalf("got %v %s %d, want %s %d", ok, data, m.HTTPStatusCode(), tt.code, tt.status)
+			}
+			decoded, ok := MultiErrorOf(ae)
+			if !ok || decoded.Len() != len(tt.errs) {
+				t.Fatalf("MultiErrorOf = %v %v", decoded, ok)
+			}
+			for i, e := range decoded.Errors {
+				if e.Err.Code != m.Errors[i].Err.Code || e.HTTPStatusCode != m.Errors[i].HTTPStatusCode {
+					t.Errorf("sub-error %d = %+v, want %+v", i, e, m.Errors[i])
+				}
+			}
+		})
+	}
+}
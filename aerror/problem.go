@@ -0,0 +1,144 @@
+package aerror
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is prepended to the code in the type member of the problem documents
+var ProblemTypePrefix = "urn:alpha:error:"
+
+var problemMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true, "code": true}
+
+// Problem is a RFC 7807 problem details document, Extensions are its additional members
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       Code
+	Extensions map[string]interface{}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			m[k] = v
+		}
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if p.Code != "" {
+		m["code"] = p.Code
+	}
+
+	return json.Marshal(m)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+		Code     Code   `json:"code"`
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*p = Problem{
+		Type:     members.Type,
+		Title:    members.Title,
+		Status:   members.Status,
+		Detail:   members.Detail,
+		Instance: members.Instance,
+		Code:     members.Code,
+	}
+	for k, v := range m {
+		if problemMembers[k] {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = map[string]interface{}{}
+		}
+		p.Extensions[k] = v
+	}
+
+	return nil
+}
+
+// ToProblem converts ae into a problem document about instance, the details become extension members
+// except those named after the standard members
+func (ae *Error) ToProblem(instance string) *Problem {
+	p := &Problem{
+		Type:     ProblemTypePrefix + string(ae.Err.Code),
+		Title:    http.StatusText(ae.HTTPStatusCode),
+		Status:   ae.HTTPStatusCode,
+		Detail:   ae.Err.Message,
+		Instance: instance,
+		Code:     ae.Err.Code,
+	}
+	if len(ae.Err.Details) > 0 {
+		p.Extensions = map[string]interface{}(ae.Err.Details)
+	}
+
+	return p
+}
+
+// ToError converts p back into an Error, its code is taken from the code member or the type
+func (p *Problem) ToError() *Error {
+	code := p.Code
+	if code == "" && strings.HasPrefix(p.Type, ProblemTypePrefix) {
+		code = Code(strings.TrimPrefix(p.Type, ProblemTypePrefix))
+	}
+	if code == "" {
+		return nil
+	}
+
+	ae := New(code).WithMessage(p.Detail)
+	if p.Status != 0 {
+		ae.HTTPStatusCode = p.Status
+	}
+	if len(p.Extensions) > 0 {
+		ae.Err.Details = Details(p.Extensions)
+	}
+
+	return ae
+}
+
+// MarshalProblemJSON encodes ae as an application/problem+json document about instance
+func MarshalProblemJSON(ae *Error, instance string) ([]byte, error) {
+	return json.Marshal(ae.ToProblem(instance))
+}
+
+// UnmarshallProblemJSON decodes a body known to be application/problem+json, the code is taken from
+// the code member or the type. UnmarshallJSON only decodes the problems of ProblemTypePrefix.
+func UnmarshallProblemJSON(data []byte) (*Error, bool) {
+	p := &Problem{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, false
+	}
+
+	ae := p.ToError()
+	return ae, ae != nil
+}
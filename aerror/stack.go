@@ -0,0 +1,154 @@
+package aerror
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const maxStackDepth = 32
+
+var stackCapture int32
+
+// SetStackCapture enables the capture of the stack where the Errors are created or wrapped,
+// it is disabled by default as it costs a runtime.Callers per Error
+func SetStackCapture(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&stackCapture, v)
+}
+
+func callers() []uintptr {
+	if atomic.LoadInt32(&stackCapture) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// Frame is a call site of the stack of an Error
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// Stack returns the captured stack of ae from its origin, without the frames of this package,
+// nil when the capture is disabled
+func (ae *Error) Stack() []Frame {
+	if len(ae.stack) == 0 {
+		return nil
+	}
+
+	var frames []Frame
+	it := runtime.CallersFrames(ae.stack)
+	for {
+		frame, more := it.Next()
+		if len(frames) > 0 || !isPackageFunction(frame.Function) {
+			frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// Origin returns the call site where ae was created or wrapped, nil when the capture is disabled
+func (ae *Error) Origin() *Frame {
+	frames := ae.Stack()
+	if len(frames) == 0 {
+		return nil
+	}
+
+	return &frames[0]
+}
+
+func isPackageFunction(function string) bool {
+	const pkg = "github.com/alphaframework/alpha/aerror."
+	return strings.HasPrefix(function, pkg) && !strings.Contains(function[len(pkg):], "/")
+}
+
+// MarshalLogObject logs the code, message, details, cause chain and origin of ae,
+// so that zap.Any("error", err) and zap.Object("error", ae) log them as structured fields
+func (ae *Error) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", string(ae.Err.Code))
+	enc.AddString("message", ae.Err.Message)
+	if ae.HTTPStatusCode != 0 {
+		enc.AddInt("http_status", ae.HTTPStatusCode)
+	}
+	if len(ae.Err.Details) > 0 {
+		if err := enc.AddReflected("details", ae.Err.Details); err != nil {
+			return err
+		}
+	}
+	if ae.cause != nil {
+		if err := enc.AddArray("causes", causeChain{ae.cause}); err != nil {
+			return err
+		}
+	}
+	if origin := ae.Origin(); origin != nil {
+		enc.AddString("origin", origin.String())
+		if err := enc.AddArray("stack", frames(ae.Stack())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type causeChain struct {
+	err error
+}
+
+func (c causeChain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for err := c.err; err != nil; err = errors.Unwrap(err) {
+		if ae, ok := err.(*Error); ok {
+			if err := enc.AppendObject(causeObject{ae}); err != nil {
+				return err
+			}
+			continue
+		}
+		enc.AppendString(err.Error())
+	}
+
+	return nil
+}
+
+// causeObject logs a cause without its own causes, which are listed by causeChain
+type causeObject struct {
+	ae *Error
+}
+
+func (c causeObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", string(c.ae.Err.Code))
+	enc.AddString("message", c.ae.Err.Message)
+	if origin := c.ae.Origin(); origin != nil {
+		enc.AddString("origin", origin.String())
+	}
+
+	return nil
+}
+
+type frames []Frame
+
+func (fs frames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range fs {
+		enc.AppendString(f.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package aerror
+
+import "errors"
+
+const (
+	// StatusDomain is the domain of the google.rpc.ErrorInfo of the Statuses
+	StatusDomain = "alphaframework"
+
+	TypeErrorInfo = "type.googleapis.com/google.rpc.ErrorInfo"
+	TypeDetails   = "type.googleapis.com/alphaframework.aerror.Details"
+)
+
+// Status mirrors google.rpc.Status, its details are the json of Any messages with their @type
+type Status struct {
+	Code    CanonicalCode            `json:"code"`
+	Message string                   `json:"message,omitempty"`
+	Details []map[string]interface{} `json:"details,omitempty"`
+}
+
+// canonicalCodes are the Codes of the Statuses without ErrorInfo
+var canonicalCodes = map[CanonicalCode]Code{
+	CanonicalCancelled:          CodeUnknown,
+	CanonicalUnknown:            CodeUnknown,
+	CanonicalInvalidArgument:    CodeBadRequest,
+	CanonicalDeadlineExceeded:   CodeTimeout,
+	CanonicalNotFound:           CodeNotFound,
+	CanonicalAlreadyExists:      CodeAlreadyExists,
+	CanonicalPermissionDenied:   CodeForbidden,
+	CanonicalResourceExhausted:  CodeTooManyRequests,
+	CanonicalFailedPrecondition: CodeBadRequest,
+	CanonicalAborted:            CodeConflict,
+	CanonicalOutOfRange:         CodeBadRequest,
+	CanonicalUnimplemented:      CodeMethodNotAllowed,
+	CanonicalInternal:           CodeInternalError,
+	CanonicalUnavailable:        CodeServiceUnavailable,
+	CanonicalDataLoss:           CodeInternalError,
+	CanonicalUnauthenticated:    CodeUnauthorized,
+}
+
+// ToStatus converts ae into a Status of the canonical code registered for its code,
+// the code is kept as the reason of an ErrorInfo and the details as an alphaframework.aerror.Details
+func (ae *Error) ToStatus() *Status {
+	s := &Status{
+		Code:    InfoOf(ae.Err.Code).CanonicalCode,
+		Message: ae.Err.Message,
+		Details: []map[string]interface{}{
+			{"@type": TypeErrorInfo, "reason": string(ae.Err.Code), "domain": StatusDomain},
+		},
+	}
+	if len(ae.Err.Details) > 0 {
+		s.Details = append(s.Details, map[string]interface{}{"@type": TypeDetails, "details": ae.Err.Details})
+	}
+
+	return s
+}
+
+// ToStatus converts err into a Status, the first *Error of its chain or CanonicalUnknown, nil if err is nil
+func ToStatus(err error) *Status {
+	if err == nil {
+		return nil
+	}
+
+	var ae *Error
+	if errors.As(err, &ae) {
+		return ae.ToStatus()
+	}
+
+	return &Status{Code: CanonicalUnknown, Message: err.Error()}
+}
+
+// FromStatus converts s back into an Error, whose code is the reason of the ErrorInfo of the
+// StatusDomain or the default Code of the canonical code, nil if s is nil or OK
+func FromStatus(s *Status) *Error {
+	if s == nil || s.Code == CanonicalOK {
+		return nil
+	}
+
+	code, ok := canonicalCodes[s.Code]
+	if !ok {
+		code = CodeUnknown
+	}
+	var details Details
+	for _, detail := range s.Details {
+		switch detail["@type"] {
+		case TypeErrorInfo:
+			reason, _ := detail["reason"].(string)
+			if domain, _ := detail["domain"].(string); domain == StatusDomain && reason != "" {
+				code = Code(reason)
+			}
+		case TypeDetails:
+			if m, ok := detail["details"].(map[string]interface{}); ok {
+				details = Details(m)
+			} else if d, ok := detail["details"].(Details); ok {
+				details = d
+			}
+		}
+	}
+
+	ae := New(code).WithMessage(s.Message)
+	if len(details) > 0 {
+		ae.Err.Details = details
+	}
+
+	return ae
+}
@@ -0,0 +1,92 @@
+package aerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// jsonRoundTrip sends s through json as a gRPC gateway would
+func jsonRoundTrip(t *testing.T, s *Status) *Status {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Status{}
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	codesMu.RLock()
+	registered := make(map[Code]CodeInfo, len(codes))
+	for code, info := range codes {
+		registered[code] = info
+	}
+	codesMu.RUnlock()
+
+	for code, info := range registered {
+		t.Run(string(code), func(t *testing.T) {
+			s := New(code).WithMessage("message of " + string(code)).ToStatus()
+			if s.Code != info.CanonicalCode {
+				t.Errorf("canonical code = %v, want %v", s.Code, info.CanonicalCode)
+			}
+
+			ae := FromStatus(jsonRoundTrip(t, s))
+			if ae.Err.Code != code || ae.Err.Message != "message of "+string(code) || ae.HTTPStatusCode != info.HTTPStatusCode {
+				t.Errorf("FromStatus() = %s %q %d", ae.Err.Code, ae.Err.Message, ae.HTTPStatusCode)
+			}
+		})
+	}
+}
+
+func TestStatusDetails(t *testing.T) {
+	details := Details{"field": "name", "limits": map[string]interface{}{"max": 10.0}}
+	ae := ErrInvalid("invalid name").WithDetails(details)
+
+	for name, s := range map[string]*Status{"direct": ae.ToStatus(), "json": jsonRoundTrip(t, ae.ToStatus())} {
+		t.Run(name, func(t *testing.T) {
+			got := FromStatus(s)
+			if got.Err.Code != CodeInvalid || !reflect.DeepEqual(got.Err.Details, details) {
+				t.Errorf("FromStatus() = %s %v, want %s %v", got.Err.Code, got.Err.Details, CodeInvalid, details)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		canonical CanonicalCode
+		code      Code
+	}{
+		{name: "not an aerror", err: errors.New("boom"), canonical: CanonicalUnknown, code: CodeUnknown},
+		{name: "wrapped aerror", err: fmt.Errorf("find: %w", ErrNotFound("missing")), canonical: CanonicalNotFound, code: CodeNotFound},
+		{name: "unregistered code", err: New("quota_exceeded"), canonical: CanonicalUnknown, code: "quota_exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ToStatus(tt.err)
+			if s.Code != tt.canonical {
+				t.Errorf("ToStatus() code = %v, want %v", s.Code, tt.canonical)
+			}
+			if ae := FromStatus(jsonRoundTrip(t, s)); ae.Err.Code != tt.code {
+				t.Errorf("FromStatus() code = %s, want %s", ae.Err.Code, tt.code)
+			}
+		})
+	}
+
+	if ToStatus(nil) != nil || FromStatus(nil) != nil || FromStatus(&Status{Code: CanonicalOK}) != nil {
+		t.Errorf("nil and OK statuses do not convert to nil")
+	}
+	if ae := FromStatus(&Status{Code: CanonicalNotFound, Message: "gone"}); ae.Err.Code != CodeNotFound || ae.Err.Message != "gone" {
+		t.Errorf("FromStatus() without ErrorInfo = %s %q", ae.Err.Code, ae.Err.Message)
+	}
+}
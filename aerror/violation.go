@@ -0,0 +1,65 @@
+package aerror
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DetailsKeyViolations is the key of the FieldViolations in the Details
+const DetailsKeyViolations = "violations"
+
+// FieldViolation is the failure of the value at Field, a dotted path such as address.city
+type FieldViolation struct {
+	Field   string      `json:"field"`
+	Rule    string      `json:"rule,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+func NewFieldViolation(field, rule string, value interface{}, format string, a ...interface{}) FieldViolation {
+	return FieldViolation{
+		Field:   field,
+		Rule:    rule,
+		Value:   value,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// ErrValidation returns an invalid Error listing violations
+func ErrValidation(violations ...FieldViolation) *Error {
+	return ErrInvalid("validation failed").WithViolations(violations...)
+}
+
+// WithViolations appends violations to the violations of the details
+func (ae *Error) WithViolations(violations ...FieldViolation) *Error {
+	if ae.Err.Details == nil {
+		ae.Err.Details = Details{}
+	}
+	ae.Err.Details[DetailsKeyViolations] = append(ae.Violations(), violations...)
+
+	return ae
+}
+
+func (ae *Error) WithFieldViolation(field, rule string, value interface{}, message string) *Error {
+	return ae.WithViolations(FieldViolation{Field: field, Rule: rule, Value: value, Message: message})
+}
+
+// Violations returns the FieldViolations of the details, also when decoded by UnmarshallJSON
+func (ae *Error) Violations() []FieldViolation {
+	switch v := ae.Err.Details[DetailsKeyViolations].(type) {
+	case nil:
+		return nil
+	case []FieldViolation:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		var violations []FieldViolation
+		if err = json.Unmarshal(data, &violations); err != nil {
+			return nil
+		}
+		return violations
+	}
+}
@@ -3,13 +3,13 @@ package alog
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/alphaframework/alpha/autil"
 	"github.com/alphaframework/alpha/autil/ahttp/request"
-	"github.com/alphaframework/alpha/forked/lumberjack"
 )
 
 const (
@@ -34,6 +34,12 @@ type Config struct {
 	MaxBackups       int
 	Compress         bool
 	BackupTimeFormat string
+	// Sinks route the levels to their outputs, each level from Level to fatal is written
+	// into its own file <application>.<level>.log if empty
+	Sinks []Sink
+	// Cumulative makes the default per level files also receive the levels above theirs,
+	// e.g. an error is written into the info, warn and error files
+	Cumulative bool
 }
 
 // InitLogger init Logger and Sugar
@@ -42,15 +48,10 @@ type Config struct {
 // level: log level (debug/info/warn/error/panic/fatal)
 // format: log format (console/json)
 func InitLogger(config *Config) error {
-	formatList := []string{"", "console", "json"}
 	if !autil.In(config.Format, formatList) {
 		return fmt.Errorf("log format: %s does not validate as in %#v", config.Format, formatList)
 	}
 
-	if config.Directory == "" {
-		return fmt.Errorf("directory is required")
-	}
-
 	if config.Level == "" {
 		config.Level = defaultLogLevel
 	}
@@ -71,57 +72,23 @@ func InitLogger(config *Config) error {
 		return err
 	}
 
-	getWriter := func(level string) *lumberjack.Logger {
-		var logPath string
-		if config.ApplicationName == "" {
-			logPath = config.Directory + "/" + level + ".log"
-		} else {
-			logPath = config.Directory + "/" + config.ApplicationName + "." + level + ".log"
-
-		}
-		logger := &lumberjack.Logger{
-			Filename:         logPath,
-			MaxSize:          config.MaxSize, // MB
-			MaxAge:           config.MaxAge,  // day
-			MaxBackups:       config.MaxBackups,
-			Compress:         config.Compress,
-			BackupTimeFormat: config.BackupTimeFormat,
-		}
-		logger.Complete()
-		return logger
-	}
-
-	encoderConfig := zapcore.EncoderConfig{
-		MessageKey:     "msg",
-		LevelKey:       "level",
-		TimeKey:        "time",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000"),
-		EncodeDuration: zapcore.SecondsDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-		EncodeName:     zapcore.FullNameEncoder,
+	sinks := config.Sinks
+	if len(sinks) == 0 {
+		sinks = defaultSinks(config, l)
 	}
-
-	encoder := zapcore.NewConsoleEncoder(encoderConfig)
-	if config.Format == "json" {
-		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	if config.Directory == "" {
+		for _, sink := range sinks {
+			for _, output := range sink.Outputs {
+				if isFileOutput(output) && !filepath.IsAbs(output) {
+					return fmt.Errorf("directory is required")
+				}
+			}
+		}
 	}
 
-	var cores []zapcore.Core
-	for l <= zapcore.FatalLevel {
-		var writers = []zapcore.WriteSyncer{zapcore.AddSync(getWriter(l.String()))}
-		cores = append(cores, zapcore.NewCore(
-			encoder,
-			zapcore.NewMultiWriteSyncer(writers...),
-			zap.NewAtomicLevelAt(l),
-		))
-		l++
+	cores, err := newCores(config, sinks, l)
+	if err != nil {
+		return err
 	}
 	core := zapcore.NewTee(cores...)
 
@@ -0,0 +1,191 @@
+package alog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/alphaframework/alpha/autil"
+	"github.com/alphaframework/alpha/forked/lumberjack"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+var formatList = []string{"", "console", "json"}
+
+// Sink routes the entries of a level, or of a range of levels, to its outputs
+type Sink struct {
+	// Outputs are stdout, stderr or files, the relative ones are in Config.Directory
+	Outputs []string `json:"outputs"`
+	// Level routes a single level, exclusive with MinLevel and MaxLevel
+	Level string `json:"level,omitempty"`
+	// MinLevel and MaxLevel bound the routed levels inclusively, unbounded if empty
+	MinLevel string `json:"min_level,omitempty"`
+	MaxLevel string `json:"max_level,omitempty"`
+	// Format is console or json, Config.Format if empty
+	Format string `json:"format,omitempty"`
+}
+
+func (s *Sink) Validate() error {
+	if len(s.Outputs) == 0 {
+		return fmt.Errorf("sink outputs are required")
+	}
+	for _, output := range s.Outputs {
+		if output == "" {
+			return fmt.Errorf("sink output must not be empty")
+		}
+	}
+	if s.Level != "" && (s.MinLevel != "" || s.MaxLevel != "") {
+		return fmt.Errorf("sink level is exclusive with min_level and max_level")
+	}
+	if !autil.In(s.Format, formatList) {
+		return fmt.Errorf("sink format: %s does not validate as in %#v", s.Format, formatList)
+	}
+	_, _, err := s.levels()
+
+	return err
+}
+
+func (s *Sink) levels() (min, max zapcore.Level, err error) {
+	min, max = zapcore.DebugLevel, zapcore.FatalLevel
+	if s.Level != "" {
+		if err = min.Set(s.Level); err != nil {
+			return
+		}
+		return min, min, nil
+	}
+	if s.MinLevel != "" {
+		if err = min.Set(s.MinLevel); err != nil {
+			return
+		}
+	}
+	if s.MaxLevel != "" {
+		if err = max.Set(s.MaxLevel); err != nil {
+			return
+		}
+	}
+	if min > max {
+		err = fmt.Errorf("sink min_level %s is above max_level %s", min, max)
+	}
+
+	return
+}
+
+// defaultSinks write each level from minimum to fatal into its own file,
+// or also the levels above it when cumulative
+func defaultSinks(config *Config, minimum zapcore.Level) []Sink {
+	var sinks []Sink
+	for l := minimum; l <= zapcore.FatalLevel; l++ {
+		name := l.String() + ".log"
+		if config.ApplicationName != "" {
+			name = config.ApplicationName + "." + name
+		}
+		sink := Sink{Outputs: []string{name}, Level: l.String()}
+		if config.Cumulative {
+			sink = Sink{Outputs: []string{name}, MinLevel: l.String()}
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks
+}
+
+func isFileOutput(output string) bool {
+	return output != OutputStdout && output != OutputStderr
+}
+
+// newCores builds a core per sink, the outputs shared by several sinks share their writer
+func newCores(config *Config, sinks []Sink, minimum zapcore.Level) ([]zapcore.Core, error) {
+	writers := map[string]zapcore.WriteSyncer{}
+	getWriter := func(output string) zapcore.WriteSyncer {
+		switch output {
+		case OutputStdout:
+			return zapcore.Lock(os.Stdout)
+		case OutputStderr:
+			return zapcore.Lock(os.Stderr)
+		}
+
+		path := output
+		if !filepath.IsAbs(path) {
+			path = config.Directory + "/" + output
+		}
+		if writer, ok := writers[path]; ok {
+			return writer
+		}
+		logger := &lumberjack.Logger{
+			Filename:         path,
+			MaxSize:          config.MaxSize, // MB
+			MaxAge:           config.MaxAge,  // day
+			MaxBackups:       config.MaxBackups,
+			Compress:         config.Compress,
+			BackupTimeFormat: config.BackupTimeFormat,
+		}
+		logger.Complete()
+		writers[path] = zapcore.AddSync(logger)
+		return writers[path]
+	}
+
+	var cores []zapcore.Core
+	for i := range sinks {
+		sink := &sinks[i]
+		if err := sink.Validate(); err != nil {
+			return nil, fmt.Errorf("log sink %d: %v", i, err)
+		}
+		min, max, _ := sink.levels()
+		if min < minimum {
+			min = minimum
+		}
+		if min > max {
+			continue
+		}
+
+		format := sink.Format
+		if format == "" {
+			format = config.Format
+		}
+		var syncers []zapcore.WriteSyncer
+		for _, output := range sink.Outputs {
+			syncers = append(syncers, getWriter(output))
+		}
+		cores = append(cores, zapcore.NewCore(
+			newEncoder(format),
+			zapcore.NewMultiWriteSyncer(syncers...),
+			zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+				return l >= min && l <= max
+			}),
+		))
+	}
+
+	return cores, nil
+}
+
+func newEncoder(format string) zapcore.Encoder {
+	encoderConfig := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "time",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000"),
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+
+	if format == "json" {
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
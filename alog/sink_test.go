@@ -0,0 +1,198 @@
+package alog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var testLevels = []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel}
+
+// logEachLevel writes a "<level> entry" message per level of testLevels
+func logEachLevel() {
+	for _, l := range testLevels {
+		if ce := Logger.Check(l, l.String()+" entry"); ce != nil {
+			ce.Write()
+		}
+	}
+}
+
+// entries returns the levels of the messages of logEachLevel found in the file
+func entries(t *testing.T, file string) []string {
+	t.Helper()
+	data, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	var levels []string
+	for _, line := range strings.Split(string(data), "\n") {
+		for _, l := range testLevels {
+			if strings.Contains(line, l.String()+" entry") {
+				levels = append(levels, l.String())
+			}
+		}
+	}
+
+	return levels
+}
+
+func initTestLogger(t *testing.T, config *Config) {
+	t.Helper()
+	logger, sugar := Logger, Sugar
+	t.Cleanup(func() {
+		Logger, Sugar = logger, sugar
+	})
+	if err := InitLogger(config); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultSinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      string
+		cumulative bool
+		want       map[string][]string
+	}{
+		{
+			name:  "each level once",
+			level: "debug",
+			want: map[string][]string{
+				"app.debug.log": {"debug"},
+				"app.info.log":  {"info"},
+				"app.warn.log":  {"warn"},
+				"app.error.log": {"error"},
+			},
+		},
+		{
+			name:  "from the minimum level",
+			level: "warn",
+			want: map[string][]string{
+				"app.debug.log": nil,
+				"app.info.log":  nil,
+				"app.warn.log":  {"warn"},
+				"app.error.log": {"error"},
+			},
+		},
+		{
+			name:       "cumulative",
+			level:      "info",
+			cumulative: true,
+			want: map[string][]string{
+				"app.debug.log": nil,
+				"app.info.log":  {"info", "warn", "error"},
+				"app.warn.log":  {"warn", "error"},
+				"app.error.log": {"error"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "alog")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			initTestLogger(t, &Config{ApplicationName: "app", Directory: dir, Level: tt.level, Cumulative: tt.cumulative})
+			logEachLevel()
+
+			for file, want := range tt.want {
+				if got := entries(t, filepath.Join(dir, file)); strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("%s has %v, want %v", file, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSinkLevelRanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	initTestLogger(t, &Config{Directory: dir, Level: "debug", Sinks: []Sink{
+		{Outputs: []string{"low.log"}, MaxLevel: "info"},
+		{Outputs: []string{"middle.log"}, MinLevel: "info", MaxLevel: "warn"},
+		{Outputs: []string{"high.log"}, MinLevel: "error"},
+		{Outputs: []string{"warn.log", "all.log"}, Level: "warn"},
+		{Outputs: []string{"all.log"}, Level: "error"},
+	}})
+	logEachLevel()
+
+	for file, want := range map[string][]string{
+		"low.log":    {"debug", "info"},
+		"middle.log": {"info", "warn"},
+		"high.log":   {"error"},
+		"warn.log":   {"warn"},
+		"all.log":    {"warn", "error"},
+	} {
+		if got := entries(t, filepath.Join(dir, file)); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s has %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestSinkStdoutStderr(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+	}()
+	if os.Stdout, err = os.Create(filepath.Join(dir, "stdout")); err != nil {
+		t.Fatal(err)
+	}
+	if os.Stderr, err = os.Create(filepath.Join(dir, "stderr")); err != nil {
+		t.Fatal(err)
+	}
+
+	initTestLogger(t, &Config{Level: "debug", Sinks: []Sink{
+		{Outputs: []string{OutputStdout}, MaxLevel: "warn"},
+		{Outputs: []string{OutputStderr}, MinLevel: "error"},
+	}})
+	logEachLevel()
+	_ = os.Stdout.Close()
+	_ = os.Stderr.Close()
+
+	if got := entries(t, filepath.Join(dir, "stdout")); strings.Join(got, ",") != "debug,info,warn" {
+		t.Errorf("stdout has %v", got)
+	}
+	if got := entries(t, filepath.Join(dir, "stderr")); strings.Join(got, ",") != "error" {
+		t.Errorf("stderr has %v", got)
+	}
+}
+
+func TestSinkValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    Sink
+		wantErr bool
+	}{
+		{name: "level", sink: Sink{Outputs: []string{"a.log"}, Level: "info"}},
+		{name: "range", sink: Sink{Outputs: []string{"a.log"}, MinLevel: "info", MaxLevel: "error"}},
+		{name: "no outputs", sink: Sink{Level: "info"}, wantErr: true},
+		{name: "level and range", sink: Sink{Outputs: []string{"a.log"}, Level: "info", MinLevel: "debug"}, wantErr: true},
+		{name: "inverted range", sink: Sink{Outputs: []string{"a.log"}, MinLevel: "error", MaxLevel: "info"}, wantErr: true},
+		{name: "unknown level", sink: Sink{Outputs: []string{"a.log"}, Level: "verbose"}, wantErr: true},
+		{name: "unknown format", sink: Sink{Outputs: []string{"a.log"}, Format: "xml"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sink.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package acrypto
 
-import "crypto/cipher"
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+var errInvalidCiphertext = errors.New("acrypto: invalid ciphertext")
 
 func CBCEncrypt(block cipher.Block, plaintext, iv []byte) ([]byte, error) {
 	blockSize := block.BlockSize()
@@ -13,9 +18,22 @@ func CBCEncrypt(block cipher.Block, plaintext, iv []byte) ([]byte, error) {
 
 func CBCDecrypt(block cipher.Block, ciphertext, iv []byte) ([]byte, error) {
 	blockSize := block.BlockSize()
+	if len(ciphertext) == 0 || len(ciphertext)%blockSize != 0 {
+		return nil, errInvalidCiphertext
+	}
 	blockMode := cipher.NewCBCDecrypter(block, iv[:blockSize])
 	plaintext := make([]byte, len(ciphertext))
 	blockMode.CryptBlocks(plaintext, ciphertext)
+	// A wrong key gives an invalid padding
+	unPadding := int(plaintext[len(plaintext)-1])
+	if unPadding == 0 || unPadding > blockSize {
+		return nil, errInvalidCiphertext
+	}
+	for _, b := range plaintext[len(plaintext)-unPadding:] {
+		if int(b) != unPadding {
+			return nil, errInvalidCiphertext
+		}
+	}
 	plaintext = PKCS7UnPadding(plaintext)
 	return plaintext, nil
 }
@@ -0,0 +1,66 @@
+package acrypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+)
+
+var (
+	testKey = []byte("0123456789abcdef")
+	testIV  = []byte("fedcba9876543210")
+)
+
+func TestAesCBCRoundTrip(t *testing.T) {
+	for _, plaintext := range []string{"", "a", "exactly 16 bytes", "more than a single block of plaintext"} {
+		ciphertext, err := AesCBCEncrypt([]byte(plaintext), testKey, testIV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := AesCBCDecrypt(ciphertext, testKey, testIV)
+		if err != nil {
+			t.Fatalf("AesCBCDecrypt(%q) error = %v", plaintext, err)
+		}
+		if string(got) != plaintext {
+			t.Errorf("AesCBCDecrypt() = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestCBCDecryptInvalid(t *testing.T) {
+	block, err := aes.NewCipher(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encrypt encrypts padded as is, without adding a padding
+	encrypt := func(padded []byte) []byte {
+		ciphertext := make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, testIV).CryptBlocks(ciphertext, padded)
+		return ciphertext
+	}
+	text := []byte("0123456789abc")
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+	}{
+		{name: "empty", ciphertext: nil},
+		{name: "partial block", ciphertext: make([]byte, aes.BlockSize+1)},
+		{name: "zero padding", ciphertext: encrypt(append(append([]byte{}, text...), 0, 0, 0))},
+		{name: "padding above the block size", ciphertext: encrypt(append(append([]byte{}, text...), 1, 2, 17))},
+		{name: "inconsistent padding", ciphertext: encrypt(append(append([]byte{}, text...), 2, 1, 3))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := CBCDecrypt(block, tt.ciphertext, testIV); err != errInvalidCiphertext {
+				t.Errorf("CBCDecrypt() = %q, %v, want %v", got, err, errInvalidCiphertext)
+			}
+		})
+	}
+
+	valid := encrypt(append(append([]byte{}, text...), 3, 3, 3))
+	if got, err := CBCDecrypt(block, valid, testIV); err != nil || !bytes.Equal(got, text) {
+		t.Errorf("CBCDecrypt() = %q, %v, want %q", got, err, text)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/md5"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
+
 	"github.com/alphaframework/alpha/autil/acrypto"
 )
 
@@ -21,6 +23,9 @@ func PBEWithMD5AndDES_Decrypt(msg, password string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if len(msgBytes) <= des.BlockSize {
+		return "", fmt.Errorf("pbe: ciphertext too short")
+	}
 	salt := msgBytes[:des.BlockSize]
 	encText := msgBytes[des.BlockSize:]
 
@@ -0,0 +1,119 @@
+// adiff reports the differences between two Application manifests.
+//
+// Usage:
+//
+//	adiff [-format text|json] [-redact pattern,...] old.yaml new.yaml
+//
+// It exits with 0 when the manifests are equal, 1 when they differ and 2 on error.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alphaframework/alpha/aconfig"
+)
+
+const (
+	exitEqual = iota
+	exitDiffer
+	exitError
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("adiff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	redact := flags.String("redact", "", "comma separated patterns of the paths to redact, the default patterns if empty")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 2 || (*format != "text" && *format != "json") {
+		fmt.Fprintln(stderr, "usage: adiff [-format text|json] [-redact pattern,...] old.yaml new.yaml")
+		return exitError
+	}
+
+	var patterns []string
+	if *redact != "" {
+		patterns = strings.Split(*redact, ",")
+	}
+	redactor, err := aconfig.NewRedactor(patterns...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	oldApp, err := load(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	newApp, err := load(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	changes, err := aconfig.Diff(oldApp, newApp, redactor)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if *format == "json" {
+		if changes == nil {
+			changes = []aconfig.Change{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(changes); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	} else {
+		for _, c := range changes {
+			switch c.Type {
+			case aconfig.ChangeAdded:
+				fmt.Fprintf(stdout, "+ %s: %s\n", c.Path, formatValue(c.New))
+			case aconfig.ChangeRemoved:
+				fmt.Fprintf(stdout, "- %s: %s\n", c.Path, formatValue(c.Old))
+			default:
+				fmt.Fprintf(stdout, "~ %s: %s -> %s\n", c.Path, formatValue(c.Old), formatValue(c.New))
+			}
+		}
+	}
+
+	if len(changes) > 0 {
+		return exitDiffer
+	}
+
+	return exitEqual
+}
+
+// load reads the manifest as written, without the environment overlay and the secret resolving
+func load(configFile string) (*aconfig.Application, error) {
+	loader := &aconfig.Loader{
+		Files:                  []string{configFile},
+		DisableEnvOverlay:      true,
+		DisableSecretResolving: true,
+	}
+
+	return loader.Load()
+}
+
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(data)
+}
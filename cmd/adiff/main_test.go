@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alphaframework/alpha/aconfig"
+)
+
+const manifest = `kind: Application
+api_version: v1
+metadata:
+  name: demo
+spec:
+  secondary_ports:
+    mysql:
+      interface:
+        name: mysql
+      options:
+        user: root
+        password: %PASSWORD%
+      matched_primary_port:
+        location:
+          address: %ADDRESS%
+          port: 3306
+`
+
+func writeManifest(t *testing.T, dir, name, password, address string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	data := strings.NewReplacer("%PASSWORD%", password, "%ADDRESS%", address).Replace(manifest)
+	if err := ioutil.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := writeManifest(t, dir, "base.yaml", "s3cret", "db1")
+	same := writeManifest(t, dir, "same.yaml", "s3cret", "db1")
+	changed := writeManifest(t, dir, "changed.yaml", "rotated", "db2")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "equal", args: []string{base, same}, wantCode: exitEqual},
+		{
+			name:     "differ",
+			args:     []string{base, changed},
+			wantCode: exitDiffer,
+			wantStdout: `~ spec.secondary_ports.mysql.matched_primary_port.location.address: "db1" -> "db2"
+~ spec.secondary_ports.mysql.options.password: "******" -> "******"
+`,
+		},
+		{
+			name:     "custom redact patterns",
+			args:     []string{"-redact", "address", base, changed},
+			wantCode: exitDiffer,
+			wantStdout: `~ spec.secondary_ports.mysql.matched_primary_port.location.address: "******" -> "******"
+~ spec.secondary_ports.mysql.options.password: "s3cret" -> "rotated"
+`,
+		},
+		{name: "missing file", args: []string{base, filepath.Join(dir, "missing.yaml")}, wantCode: exitError, wantStderr: "missing.yaml"},
+		{name: "one file", args: []string{base}, wantCode: exitError, wantStderr: "usage"},
+		{name: "unknown format", args: []string{"-format", "xml", base, same}, wantCode: exitError, wantStderr: "usage"},
+		{name: "invalid pattern", args: []string{"-redact", "(", base, same}, wantCode: exitError, wantStderr: "redact pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("run() = %d, want %d, stderr %s", code, tt.wantCode, stderr.String())
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := writeManifest(t, dir, "base.yaml", "s3cret", "db1")
+	changed := writeManifest(t, dir, "changed.yaml", "rotated", "db1")
+
+	for _, tt := range []struct {
+		name     string
+		newFile  string
+		wantCode int
+		want     []aconfig.Change
+	}{
+		{name: "equal", newFile: base, wantCode: exitEqual, want: []aconfig.Change{}},
+		{name: "differ", newFile: changed, wantCode: exitDiffer, want: []aconfig.Change{{
+			Path: "spec.secondary_ports.mysql.options.password",
+			Type: aconfig.ChangeModified,
+			Old:  aconfig.RedactedValue,
+			New:  aconfig.RedactedValue,
+		}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run([]string{"-format", "json", base, tt.newFile}, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d, stderr %s", code, tt.wantCode, stderr.String())
+			}
+			var changes []aconfig.Change
+			if err := json.Unmarshal(stdout.Bytes(), &changes); err != nil {
+				t.Fatalf("stdout %q: %v", stdout.String(), err)
+			}
+			if len(changes) != len(tt.want) {
+				t.Fatalf("changes = %v, want %v", changes, tt.want)
+			}
+			for i := range changes {
+				if changes[i] != tt.want[i] {
+					t.Errorf("change %d = %v, want %v", i, changes[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
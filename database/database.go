@@ -1,16 +1,22 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
-	"time"
+	"strings"
+	"sync/atomic"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 	"github.com/alphaframework/alpha/aconfig"
 	"github.com/alphaframework/alpha/alog"
 	"github.com/alphaframework/alpha/alog/gormwrapper"
+	"github.com/alphaframework/alpha/discovery"
 )
 
 func MustNewDBWith(portName aconfig.PortName, appConfig *aconfig.Application, driver string, commonConfig *aconfig.Database) *gorm.DB {
@@ -22,24 +28,34 @@ func MustNewDBWith(portName aconfig.PortName, appConfig *aconfig.Application, dr
 	return db
 }
 
+// NewDBWith opens the database of the secondary port portName. Its locations are located again
+// for each new connection and dialed in turn, so that the refreshed and extra endpoints of the
+// discovery are used as the connections are renewed after connection_max_life_seconds.
 func NewDBWith(portName aconfig.PortName, appConfig *aconfig.Application, driver string, commonConfig *aconfig.Database) (*gorm.DB, error) {
-	location := appConfig.GetMatchedPrimaryPortLocation(portName)
-	if location == nil {
-		return nil, fmt.Errorf("missing matched primaryport location(%s)", portName)
+	location, err := discovery.LocateOne(context.Background(), portName, appConfig)
+	if err != nil {
+		return nil, err
 	}
 	options := appConfig.GetSecondaryPort(portName).Options
 	if options == nil {
 		return nil, fmt.Errorf("missing options for secondary port (%s)", portName)
 	}
 
-	return NewDB(driver, formatDNS(location, options), commonConfig)
+	alog.Sugar.Infof("database.NewDBWith: driver(%s) port(%s) dsn(%s)", driver, portName, redactDSN(formatDNS(location, options)))
+	connector := &locatingConnector{portName: portName, appConfig: appConfig, options: options}
+
+	return openDB(mysql.New(mysql.Config{Conn: sql.OpenDB(connector)}), commonConfig)
 }
 
 func NewDB(driver, dsn string, commonConfig *aconfig.Database) (*gorm.DB, error) {
-	alog.Sugar.Infof("database.NewDB: driver(%s) dsn(%s)", driver, dsn)
+	alog.Sugar.Infof("database.NewDB: driver(%s) dsn(%s)", driver, redactDSN(dsn))
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: gormwrapper.New(alog.Sugar, gormwrapper.Config{
-		SlowThreshold: time.Duration(commonConfig.SlowThresholdMilliseconds) * time.Millisecond,
+	return openDB(mysql.Open(dsn), commonConfig)
+}
+
+func openDB(dialector gorm.Dialector, commonConfig *aconfig.Database) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: gormwrapper.New(alog.Sugar, gormwrapper.Config{
+		SlowThreshold: commonConfig.SlowThreshold(),
 		LogLevel:      gormlogger.Info,
 	})})
 
@@ -53,14 +69,52 @@ func NewDB(driver, dsn string, commonConfig *aconfig.Database) (*gorm.DB, error)
 		alog.Sugar.Errorf("database.NewDB get standard DB failed: %v", err)
 		return nil, err
 	}
-	stdDB.SetMaxOpenConns(commonConfig.MaxOpenConnections)
-	stdDB.SetMaxIdleConns(commonConfig.MaxIdleConnections)
-	stdDB.SetConnMaxLifetime(time.Duration(commonConfig.ConnectionMaxLifeSeconds) * time.Second)
-	stdDB.SetConnMaxIdleTime(time.Duration(commonConfig.ConnectionMaxIdleSeconds) * time.Second)
+	commonConfig.ApplyTo(stdDB)
 
 	return db, nil
 }
 
+// locatingConnector dials the locations of a secondary port, located for each new connection
+// and tried in turn from a rotating start
+type locatingConnector struct {
+	portName  aconfig.PortName
+	appConfig *aconfig.Application
+	options   aconfig.KV
+	next      uint32
+}
+
+func (c *locatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	locations, err := discovery.Locate(ctx, c.portName, c.appConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int(atomic.AddUint32(&c.next, 1))
+	var connectErr error
+	for i := range locations {
+		location := locations[(start+i)%len(locations)]
+		config, err := mysqldriver.ParseDSN(formatDNS(&location, c.options))
+		if err != nil {
+			return nil, err
+		}
+		connector, err := mysqldriver.NewConnector(config)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := connector.Connect(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		connectErr = err
+	}
+
+	return nil, connectErr
+}
+
+func (c *locatingConnector) Driver() driver.Driver {
+	return mysqldriver.MySQLDriver{}
+}
+
 func formatDNS(location *aconfig.Location, options aconfig.KV) string {
 	user := options.GetString("user")
 	password := options.GetString("password")
@@ -69,6 +123,20 @@ func formatDNS(location *aconfig.Location, options aconfig.KV) string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True", user, password, location.Address, location.Port, database)
 }
 
+// redactDSN hides the password of a user:password@protocol(address)/dbname DSN
+func redactDSN(dsn string) string {
+	at := strings.LastIndex(dsn, "@")
+	if at < 0 {
+		return dsn
+	}
+	colon := strings.Index(dsn[:at], ":")
+	if colon < 0 {
+		return dsn
+	}
+
+	return dsn[:colon+1] + "***" + dsn[at:]
+}
+
 func IsRecordNotfound(err error) bool {
 	return errors.Is(err, gorm.ErrRecordNotFound)
 }
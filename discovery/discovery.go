@@ -0,0 +1,148 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alphaframework/alpha/aconfig"
+)
+
+// Resolver resolves the locations of the application matched by the secondary port portName
+type Resolver interface {
+	Resolve(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error)
+}
+
+type ResolverFunc func(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error) {
+	return f(ctx, applicationName, portName)
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultResolver Resolver
+)
+
+// SetDefault sets the Resolver consulted by Locate, nil keeps the static locations only
+func SetDefault(r Resolver) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultResolver = r
+}
+
+func Default() Resolver {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+
+	return defaultResolver
+}
+
+// Locate returns the locations of the secondary port portName. The matched application name is
+// resolved by the default Resolver, the static matched location is the fallback.
+func Locate(ctx context.Context, portName aconfig.PortName, appConfig *aconfig.Application) ([]aconfig.Location, error) {
+	matched := appConfig.GetMatchedPrimaryPort(portName)
+	if matched == nil {
+		return nil, fmt.Errorf("missing matched primaryport (port_name: %s)", portName)
+	}
+
+	var resolveErr error
+	if r := Default(); r != nil && matched.ApplicationName != "" {
+		locations, err := r.Resolve(ctx, matched.ApplicationName, portName)
+		if err == nil && len(locations) > 0 {
+			return locations, nil
+		}
+		resolveErr = err
+	}
+
+	if matched.Location != nil {
+		return []aconfig.Location{*matched.Location}, nil
+	}
+	if resolveErr != nil {
+		return nil, fmt.Errorf("resolve matched primaryport (port_name: %s, application_name: %s): %v", portName, matched.ApplicationName, resolveErr)
+	}
+
+	return nil, fmt.Errorf("missing matched primaryport location (port_name: %s)", portName)
+}
+
+// LocateOne returns the first location of Locate
+func LocateOne(ctx context.Context, portName aconfig.PortName, appConfig *aconfig.Application) (*aconfig.Location, error) {
+	locations, err := Locate(ctx, portName, appConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &locations[0], nil
+}
+
+// Chain returns a Resolver trying resolvers in order until one returns locations
+func Chain(resolvers ...Resolver) Resolver {
+	return ResolverFunc(func(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error) {
+		var lastErr error
+		for _, r := range resolvers {
+			locations, err := r.Resolve(ctx, applicationName, portName)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if len(locations) > 0 {
+				return locations, nil
+			}
+		}
+		if lastErr != nil {
+			return nil, lastErr
+		}
+
+		return nil, fmt.Errorf("no location for %s/%s", applicationName, portName)
+	})
+}
+
+type cacheEntry struct {
+	locations []aconfig.Location
+	expiresAt time.Time
+}
+
+// Cache caches the locations of a Resolver for TTL, stale locations are kept
+// when the refresh fails
+type Cache struct {
+	resolver Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewCache(resolver Resolver, ttl time.Duration) *Cache {
+	return &Cache{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+func (c *Cache) Resolve(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error) {
+	key := applicationName + "/" + string(portName)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.locations, nil
+	}
+
+	locations, err := c.resolver.Resolve(ctx, applicationName, portName)
+	if err != nil || len(locations) == 0 {
+		if ok {
+			return entry.locations, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{locations: locations, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return locations, nil
+}
@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alphaframework/alpha/aconfig"
+)
+
+// SRV resolves the _portName._Proto.applicationName[.Domain] DNS SRV records
+type SRV struct {
+	// Proto is tcp if empty
+	Proto  string
+	Domain string
+	// Resolver is net.DefaultResolver if nil
+	Resolver *net.Resolver
+}
+
+func (s *SRV) Resolve(ctx context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error) {
+	proto := s.Proto
+	if proto == "" {
+		proto = "tcp"
+	}
+	name := applicationName
+	if s.Domain != "" {
+		name += "." + strings.TrimPrefix(s.Domain, ".")
+	}
+	resolver := s.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	_, records, err := resolver.LookupSRV(ctx, string(portName), proto, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The records are sorted by priority and randomized by weight
+	locations := make([]aconfig.Location, 0, len(records))
+	for _, record := range records {
+		locations = append(locations, aconfig.Location{
+			Address: strings.TrimSuffix(record.Target, "."),
+			Port:    int(record.Port),
+		})
+	}
+
+	return locations, nil
+}
+
+// Static resolves from a table keyed by "application_name/port_name" or "application_name"
+type Static map[string][]aconfig.Location
+
+func (s Static) Resolve(_ context.Context, applicationName string, portName aconfig.PortName) ([]aconfig.Location, error) {
+	if locations, ok := s[applicationName+"/"+string(portName)]; ok {
+		return locations, nil
+	}
+	if locations, ok := s[applicationName]; ok {
+		return locations, nil
+	}
+
+	return nil, fmt.Errorf("no location for %s/%s", applicationName, portName)
+}
+
+// NewHostsFile parses a static hosts file into a Static resolver, one entry per line:
+//
+//	# application_name[/port_name] address:port [address:port ...]
+//	user-db/mysql 10.0.0.1:3306 10.0.0.2:3306
+func NewHostsFile(path string) (Static, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	static := Static{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: missing location", path, n)
+		}
+		for _, field := range fields[1:] {
+			host, port, err := net.SplitHostPort(field)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+			}
+			p, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid port %q", path, n, port)
+			}
+			static[fields[0]] = append(static[fields[0]], aconfig.Location{Address: host, Port: p})
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return static, nil
+}
@@ -11,6 +11,7 @@ import (
 	_ "github.com/alphaframework/alpha/autil/ahttp"
 	_ "github.com/alphaframework/alpha/autil/ahttp/request"
 	_ "github.com/alphaframework/alpha/database"
+	_ "github.com/alphaframework/alpha/discovery"
 	_ "github.com/alphaframework/alpha/ginwrapper"
 	_ "github.com/alphaframework/alpha/httpclient"
 	_ "github.com/alphaframework/alpha/httpserver/rsp"
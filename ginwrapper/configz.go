@@ -0,0 +1,84 @@
+package ginwrapper
+
+import (
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gin-gonic/gin"
+
+	"github.com/alphaframework/alpha/aconfig"
+	"github.com/alphaframework/alpha/aerror"
+	"github.com/alphaframework/alpha/httpserver/rsp"
+)
+
+type ConfigzOptions struct {
+	Application *aconfig.Application
+	// Watcher provides the current Application instead of Application
+	Watcher *aconfig.Watcher
+	Common  *aconfig.Common
+	// RedactPatterns match the dotted paths of the hidden values, aconfig.DefaultRedactPatterns if empty
+	RedactPatterns []string
+}
+
+type configz struct {
+	Application interface{}       `json:"application,omitempty"`
+	Common      interface{}       `json:"common,omitempty"`
+	Sources     map[string]string `json:"sources,omitempty"`
+}
+
+// ConfigzHandler serves the effective config with its secrets redacted and the source of each value,
+// as YAML with ?format=yaml or an Accept header asking for yaml, as JSON otherwise.
+// It fails on invalid RedactPatterns.
+func ConfigzHandler(options *ConfigzOptions) (gin.HandlerFunc, error) {
+	redactor, err := aconfig.NewRedactor(options.RedactPatterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		var resp configz
+
+		application := options.Application
+		if options.Watcher != nil {
+			application = options.Watcher.Application()
+		}
+		if application != nil {
+			tree, err := redactor.RedactApplication(application)
+			if err != nil {
+				rsp.Error(c, aerror.Wrap(err, aerror.CodeInternalError, "configz"))
+				return
+			}
+			resp.Application = tree
+			resp.Sources = application.Sources()
+		}
+		if options.Common != nil {
+			tree, err := redactor.RedactValue(options.Common, "", nil)
+			if err != nil {
+				rsp.Error(c, aerror.Wrap(err, aerror.CodeInternalError, "configz"))
+				return
+			}
+			resp.Common = tree
+		}
+
+		if c.Query("format") == "yaml" || strings.Contains(c.GetHeader("Accept"), "yaml") {
+			data, err := yaml.Marshal(resp)
+			if err != nil {
+				rsp.Error(c, aerror.Wrap(err, aerror.CodeInternalError, "configz"))
+				return
+			}
+			c.Data(200, "application/x-yaml; charset=utf-8", data)
+			return
+		}
+
+		c.JSON(200, resp)
+	}, nil
+}
+
+func MustConfigzHandler(options *ConfigzOptions) gin.HandlerFunc {
+	handler, err := ConfigzHandler(options)
+	if err != nil {
+		panic(err)
+	}
+
+	return handler
+}
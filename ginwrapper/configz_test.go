@@ -0,0 +1,54 @@
+package ginwrapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/alphaframework/alpha/aconfig"
+	"github.com/alphaframework/alpha/aerror"
+	"github.com/alphaframework/alpha/alog"
+)
+
+func TestConfigzHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{name: "default", patterns: nil},
+		{name: "valid", patterns: []string{"password$", "token"}},
+		{name: "invalid", patterns: []string{"("}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := ConfigzHandler(&ConfigzOptions{RedactPatterns: tt.patterns})
+			if (err != nil) != tt.wantErr || (handler == nil) != tt.wantErr {
+				t.Fatalf("ConfigzHandler() = %v, %v, want error %v", handler != nil, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigzInvalidPatterns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	alog.Logger = zap.NewNop()
+	alog.Sugar = alog.Logger.Sugar()
+	engine := New(&Options{Configz: &ConfigzOptions{Common: &aconfig.Common{}, RedactPatterns: []string{"("}}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/configz", nil)
+	req.Header.Set("Accept", aerror.ProblemContentType)
+	engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, aerror.ProblemContentType) {
+		t.Fatalf("content type = %q, want %q", ct, aerror.ProblemContentType)
+	}
+}
@@ -3,7 +3,9 @@ package ginwrapper
 import (
 	"time"
 
+	"github.com/alphaframework/alpha/aerror"
 	"github.com/alphaframework/alpha/alog"
+	"github.com/alphaframework/alpha/httpserver/rsp"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,6 +21,11 @@ type Options struct {
 	ReadyzHandler func(c *gin.Context)
 	// ConfigzHandler checks if the app's config is working
 	ConfigzHandler func(c *gin.Context)
+	// Configz serves the effective config on /configz when ConfigzHandler is nil
+	Configz *ConfigzOptions
+	// JSONFieldNames names the fields of the binding violations by their json tags, see rsp.RegisterJSONFieldNames.
+	// It changes gin's validator for the whole process.
+	JSONFieldNames bool
 }
 
 func (o *Options) complete() {
@@ -28,6 +35,16 @@ func (o *Options) complete() {
 	if o.ReadyzHandler == nil {
 		o.ReadyzHandler = defaultHealthHandler
 	}
+	if o.ConfigzHandler == nil && o.Configz != nil {
+		handler, err := ConfigzHandler(o.Configz)
+		if err != nil {
+			// The misconfiguration is reported by /configz rather than failing New
+			handler = func(c *gin.Context) {
+				rsp.Error(c, aerror.Wrap(err, aerror.CodeInternalError, "configz"))
+			}
+		}
+		o.ConfigzHandler = handler
+	}
 	if o.ConfigzHandler == nil {
 		o.ConfigzHandler = defaultHealthHandler
 	}
@@ -38,6 +55,9 @@ func New(options *Options) *gin.Engine {
 		options = &Options{}
 	}
 	options.complete()
+	if options.JSONFieldNames {
+		rsp.RegisterJSONFieldNames()
+	}
 
 	r := gin.New()
 	r.Use(GinResponseBodyLogMiddleware())
@@ -90,8 +90,10 @@ func Ginzap(logger *zap.Logger, timeFormat string, utc bool) gin.HandlerFunc {
 
 		if len(c.Errors) > 0 {
 			// Append error field if this is an erroneous request.
-			for _, e := range c.Errors.Errors() {
-				logger.Error(e,
+			for _, e := range c.Errors {
+				// The *aerror.Error are logged with their cause chain and origin
+				logger.Error(e.Error(),
+					zap.Any("error", e.Err),
 					zap.String(alog.RequestIdKey, requestId))
 			}
 		}
@@ -0,0 +1,62 @@
+package ginwrapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/alphaframework/alpha/aerror"
+	"github.com/alphaframework/alpha/httpserver/rsp"
+)
+
+func TestGinzapLogsErrorOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	aerror.SetStackCapture(true)
+	defer aerror.SetStackCapture(false)
+
+	tests := []struct {
+		name    string
+		err     error
+		wantLog bool
+	}{
+		{name: "internal without cause", err: aerror.ErrInternalError("boom"), wantLog: true},
+		{name: "not found with cause", err: aerror.Wrap(http.ErrNoCookie, aerror.CodeNotFound, "missing"), wantLog: true},
+		{name: "not found without cause", err: aerror.ErrNotFound("missing"), wantLog: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			r := gin.New()
+			r.Use(Ginzap(zap.New(core), time.RFC3339, true))
+			r.GET("/", func(c *gin.Context) {
+				rsp.Error(c, tt.err)
+			})
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			entries := logs.All()
+			if !tt.wantLog {
+				if len(entries) != 0 {
+					t.Fatalf("logged %d errors, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("logged %d errors, want 1", len(entries))
+			}
+			logged, ok := entries[0].ContextMap()["error"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("error field = %v", entries[0].ContextMap()["error"])
+			}
+			if origin, _ := logged["origin"].(string); !strings.Contains(origin, "middleware_test.go") {
+				t.Errorf("origin = %q, want the test file", origin)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ require (
 	github.com/BurntSushi/toml v0.3.1
 	github.com/ghodss/yaml v1.0.0
 	github.com/gin-gonic/gin v1.6.3
+	github.com/go-playground/validator/v10 v10.2.0
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/go-resty/resty/v2 v2.3.0
 	github.com/google/uuid v1.1.2
 	github.com/spf13/cast v1.3.1
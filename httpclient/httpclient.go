@@ -1,12 +1,16 @@
 package httpclient
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alphaframework/alpha/aconfig"
 	"github.com/alphaframework/alpha/alog"
+	"github.com/alphaframework/alpha/discovery"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 )
@@ -26,20 +30,44 @@ func NewResty(sugar *zap.SugaredLogger) *resty.Client {
 	return client
 }
 
+// NewRestyWith returns a client of the secondary port portName. The locations are located again
+// before each request with a relative URL and used in turn, the host URL is the fallback when
+// the locating fails.
 func NewRestyWith(portName aconfig.PortName, appConfig *aconfig.Application, protocol string) (*resty.Client, error) {
-	location := appConfig.GetMatchedPrimaryPortLocation(portName)
-	if location == nil {
-		return nil, fmt.Errorf("missing matched primaryport location (port_name: %s)", portName)
+	location, err := discovery.LocateOne(context.Background(), portName, appConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	client := NewResty(alog.Sugar)
+	client.SetHostURL(hostURL(protocol, location))
+
+	var next uint32
+	client.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
+		if u, err := url.Parse(r.URL); err != nil || u.IsAbs() {
+			return nil
+		}
+		locations, err := discovery.Locate(r.Context(), portName, appConfig)
+		if err != nil {
+			return nil
+		}
+		location := locations[int(atomic.AddUint32(&next, 1))%len(locations)]
+		if !strings.HasPrefix(r.URL, "/") {
+			r.URL = "/" + r.URL
+		}
+		r.URL = hostURL(protocol, &location) + r.URL
+		return nil
+	})
+
+	return client, nil
+}
+
+func hostURL(protocol string, location *aconfig.Location) string {
 	// Eliminat the interference of protocol in address
 	address := strings.TrimPrefix(location.Address, "http://")
 	address = strings.TrimPrefix(address, "https://")
-	hostURL := fmt.Sprintf("%s%s:%d", protocol, address, location.Port)
-	client.SetHostURL(hostURL)
 
-	return client, nil
+	return fmt.Sprintf("%s%s:%d", protocol, address, location.Port)
 }
 
 func MustNewRestyWith(portName aconfig.PortName, appConfig *aconfig.Application, protocol string) *resty.Client {
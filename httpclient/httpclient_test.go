@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/alphaframework/alpha/aconfig"
+	"github.com/alphaframework/alpha/alog"
+	"github.com/alphaframework/alpha/discovery"
+)
+
+func serverLocation(t *testing.T, name string) (*httptest.Server, aconfig.Location) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name))
+	}))
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+
+	return server, aconfig.Location{Address: host, Port: p}
+}
+
+func TestNewRestyWithRelocates(t *testing.T) {
+	alog.Logger = zap.NewNop()
+	alog.Sugar = alog.Logger.Sugar()
+
+	a, locationA := serverLocation(t, "a")
+	defer a.Close()
+	b, locationB := serverLocation(t, "b")
+	defer b.Close()
+
+	static := discovery.Static{"users": {locationA}}
+	discovery.SetDefault(static)
+	defer discovery.SetDefault(nil)
+
+	appConfig := &aconfig.Application{}
+	appConfig.Spec.SecondaryPorts = map[aconfig.PortName]aconfig.SecondaryPort{
+		"users": {MatchedPrimaryPort: &aconfig.MatchedPrimaryPort{ApplicationName: "users"}},
+	}
+	client, err := NewRestyWith("users", appConfig, "http://")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func() string {
+		resp, err := client.R().Get("/ping")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.String()
+	}
+	if got := get(); got != "a" {
+		t.Errorf("got %q, want a", got)
+	}
+
+	// The refreshed locations are used by the next requests, in turn
+	static["users"] = []aconfig.Location{locationA, locationB}
+	seen := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		seen[get()] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("requests reached %v, want both locations", seen)
+	}
+
+	// The host URL is the fallback when the locating fails
+	delete(static, "users")
+	if got := get(); got != "a" {
+		t.Errorf("got %q, want a", got)
+	}
+}
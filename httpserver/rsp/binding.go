@@ -0,0 +1,81 @@
+package rsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/alphaframework/alpha/aerror"
+)
+
+var registerJSONFieldNamesOnce sync.Once
+
+// RegisterJSONFieldNames makes the gin validator report the fields by their json names,
+// so that the violations name the fields as the clients send them. It changes binding.Validator,
+// which is global to the process, and cannot be undone.
+func RegisterJSONFieldNames() {
+	registerJSONFieldNamesOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+			name := strings.Split(sf.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return sf.Name
+			}
+			return name
+		})
+	})
+}
+
+// bindingError translates the errors of the gin binding and validator into aerror
+func bindingError(err error) (*aerror.Error, bool) {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		violations := make([]aerror.FieldViolation, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			field := fieldPath(fe.Namespace())
+			if fe.Param() != "" {
+				violations = append(violations, aerror.NewFieldViolation(field, fe.Tag(), fe.Value(),
+					"%s must satisfy %s=%s", field, fe.Tag(), fe.Param()))
+			} else {
+				violations = append(violations, aerror.NewFieldViolation(field, fe.Tag(), fe.Value(),
+					"%s must satisfy %s", field, fe.Tag()))
+			}
+		}
+		return aerror.ErrValidation(violations...).WithCause(err), true
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		field := typeError.Field
+		return aerror.ErrValidation(aerror.NewFieldViolation(field, "type", nil,
+			"%s must be %s, got %s", field, typeError.Type, typeError.Value)).WithCause(err), true
+	}
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return aerror.ErrBadRequest().WithError(err), true
+	}
+
+	return nil, false
+}
+
+// fieldPath drops the name of the top level struct from the namespace of a validator field
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
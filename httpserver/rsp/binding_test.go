@@ -0,0 +1,93 @@
+package rsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/alphaframework/alpha/aerror"
+)
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"User.name", "name"},
+		{"User.address.city", "address.city"},
+		{"User.tags[1]", "tags[1]"},
+		{"name", "name"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := fieldPath(tt.namespace); got != tt.want {
+			t.Errorf("fieldPath(%q) = %q, want %q", tt.namespace, got, tt.want)
+		}
+	}
+}
+
+type bindingAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type bindingUser struct {
+	Name    string         `json:"name" binding:"required"`
+	Age     int            `json:"age" binding:"min=18"`
+	Address bindingAddress `json:"address"`
+	Note    string         `binding:"max=3"`
+}
+
+func TestBindingError(t *testing.T) {
+	RegisterJSONFieldNames()
+
+	decode := func(data string) error {
+		var u bindingUser
+		return json.Unmarshal([]byte(data), &u)
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		ok         bool
+		code       aerror.Code
+		violations []string
+	}{
+		{
+			name:       "validation",
+			err:        binding.Validator.ValidateStruct(&bindingUser{Age: 17, Note: "long"}),
+			ok:         true,
+			code:       aerror.CodeInvalid,
+			violations: []string{"name required", "age min", "address.city required", "Note max"},
+		},
+		{name: "type", err: decode(`{"age":"old"}`), ok: true, code: aerror.CodeInvalid, violations: []string{"age type"}},
+		{name: "syntax", err: decode(`{"name":`), ok: true, code: aerror.CodeBadRequest},
+		{name: "empty body", err: io.EOF, ok: true, code: aerror.CodeBadRequest},
+		{name: "other", err: errors.New("boom"), ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae, ok := bindingError(tt.err)
+			if ok != tt.ok {
+				t.Fatalf("bindingError(%v) ok = %v, want %v", tt.err, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if ae.Err.Code != tt.code || errors.Unwrap(ae) == nil {
+				t.Errorf("bindingError() = %s caused by %v", ae.Err.Code, errors.Unwrap(ae))
+			}
+
+			var violations []string
+			for _, v := range ae.Violations() {
+				violations = append(violations, v.Field+" "+v.Rule)
+			}
+			if strings.Join(violations, ",") != strings.Join(tt.violations, ",") {
+				t.Errorf("violations = %v, want %v", violations, tt.violations)
+			}
+		})
+	}
+}
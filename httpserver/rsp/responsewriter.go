@@ -1,17 +1,98 @@
 package rsp
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/alphaframework/alpha/aerror"
 )
 
+// Error answers err, nothing is written when err is nil or an empty *aerror.MultiError
 func Error(c *gin.Context, err error) {
 	var errResp *aerror.Error
 	var ok bool
 
-	if errResp, ok = err.(*aerror.Error); !ok {
-		errResp = aerror.ErrInternalError().WithMessage(err.Error())
+	if err == nil {
+		return
+	}
+	// The errors wrapping an *aerror.Error or an *aerror.MultiError answer as them
+	var m *aerror.MultiError
+	if errors.As(err, &m) {
+		if m.Len() == 0 {
+			return
+		}
+		errResp, ok = m.ToError(), true
+	} else {
+		ok = errors.As(err, &errResp)
+	}
+	if !ok {
+		if errResp, ok = bindingError(err); !ok {
+			errResp = aerror.ErrInternalError().WithError(err)
+		}
+	}
+	if errors.Unwrap(errResp) != nil || errResp.HTTPStatusCode >= http.StatusInternalServerError {
+		// The cause and origin are only logged, through the errors of the context
+		_ = c.Error(errResp)
+	}
+
+	errResp = errResp.Localize(aerror.MatchLanguage(c.GetHeader("Accept-Language")))
+
+	if acceptsProblem(c.GetHeader("Accept")) {
+		data, err := aerror.MarshalProblemJSON(errResp, c.Request.URL.Path)
+		if err == nil {
+			c.Data(errResp.HTTPStatusCode, aerror.ProblemContentType, data)
+			return
+		}
 	}
 
 	c.JSON(errResp.HTTPStatusCode, errResp)
 }
+
+// acceptsProblem reports whether the Accept header prefers application/problem+json over application/json,
+// the media ranges are matched by hand since gin's NegotiateFormat panics on types such as application/jsonl
+func acceptsProblem(accept string) bool {
+	return acceptQuality(accept, aerror.ProblemContentType) > acceptQuality(accept, gin.MIMEJSON)
+}
+
+// acceptQuality returns the quality of mediaType from its most specific range in accept
+func acceptQuality(accept, mediaType string) float64 {
+	mainType := strings.Split(mediaType, "/")[0] + "/*"
+	quality, specificity := 0.0, 0
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+		switch mediaRange {
+		case mediaType:
+			s = 3
+		case mainType:
+			s = 2
+		case "*/*":
+			s = 1
+		default:
+			continue
+		}
+		if s < specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if s > specificity || q > quality {
+			quality, specificity = q, s
+		}
+	}
+
+	return quality
+}
@@ -0,0 +1,112 @@
+package rsp
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alphaframework/alpha/aerror"
+)
+
+func TestAcceptsProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/*", false},
+		{"application/problem+json", true},
+		{"application/problem+json, application/json;q=0.5", true},
+		{"application/json, application/problem+json;q=0.5", false},
+		{"application/json;q=0.5, application/problem+json", true},
+		{"application/problem+json;q=0", false},
+		{"application/json-patch+json", false},
+		{"application/jsonl", false},
+		{"text/html, */*;q=0.1", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsProblem(tt.accept); got != tt.want {
+			t.Errorf("acceptsProblem(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestErrorContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		Error(c, aerror.ErrNotFound("missing"))
+	})
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json; charset=utf-8"},
+		{"application/json-patch+json", "application/json; charset=utf-8"},
+		{"application/jsonl", "application/json; charset=utf-8"},
+		{"application/problem+json", aerror.ProblemContentType},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		r.ServeHTTP(w, req)
+
+		if w.Code != 404 {
+			t.Errorf("Accept %q: status = %d, want 404", tt.accept, w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.contentType)
+		}
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   aerror.Code
+	}{
+		{"aerror", aerror.ErrNotFound(), 404, aerror.CodeNotFound},
+		{"wrapped aerror", fmt.Errorf("ctx: %w", aerror.ErrNotFound()), 404, aerror.CodeNotFound},
+		{"multi", (&aerror.MultiError{}).Append(aerror.ErrGone(), aerror.ErrGone()), 410, aerror.CodeGone},
+		{"wrapped multi", fmt.Errorf("ctx: %w", (&aerror.MultiError{}).Append(aerror.ErrGone())), 410, aerror.CodeGone},
+		{"plain", fmt.Errorf("boom"), 500, aerror.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			Error(c, tt.err)
+
+			ae, ok := aerror.UnmarshallJSON(w.Body.Bytes())
+			if w.Code != tt.status || !ok || ae.Err.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", w.Code, w.Body.String(), tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestErrorWithoutError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, err := range []error{nil, &aerror.MultiError{}} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		Error(c, err)
+
+		if c.Writer.Written() {
+			t.Errorf("Error(%#v) wrote %d %s", err, w.Code, w.Body.String())
+		}
+	}
+}